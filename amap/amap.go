@@ -3,6 +3,8 @@ package amap
 import (
 	"encoding/xml"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/codingsince1985/geo-golang"
@@ -46,17 +48,51 @@ type (
 					Street    string `xml:"street"`
 				} `xml:"streetNumber"`
 			} `xml:"addressComponent"`
+			Pois []poi `xml:"pois>poi"`
 		} `xml:"regeocode"`
+		// Place search response
+		Pois []poi `xml:"pois>poi"`
+	}
+	// poi is a point of interest found by a place search or near a reverse geocoded location
+	poi struct {
+		ID       string `xml:"id"`
+		Name     string `xml:"name"`
+		Type     string `xml:"type"` // categories from broadest to narrowest, separated by ';'
+		Typecode string `xml:"typecode"`
+		Address  string `xml:"address"`
+		Location string `xml:"location"`
+		Distance string `xml:"distance"`
 	}
 )
 
 const (
 	statusOK = 1
+	// batchSize is the most addresses a batch request may carry
+	batchSize = 10
 )
 
+// amapPrecisions maps the level of a geocode onto a geo.Precision
+var amapPrecisions = map[string]geo.Precision{
+	"国家":     geo.PrecisionCountry,
+	"省":      geo.PrecisionRegion,
+	"市":      geo.PrecisionCity,
+	"区县":     geo.PrecisionCity,
+	"开发区":    geo.PrecisionCity,
+	"乡镇":     geo.PrecisionCity,
+	"村庄":     geo.PrecisionCity,
+	"热点商圈":   geo.PrecisionCity,
+	"道路":     geo.PrecisionStreet,
+	"道路交叉路口": geo.PrecisionStreet,
+	"门牌号":    geo.PrecisionRooftop,
+	"单元号":    geo.PrecisionRooftop,
+	"楼栋":     geo.PrecisionRooftop,
+	"兴趣点":    geo.PrecisionRooftop,
+}
+
 var r = 1000
 
-// Geocoder constructs AMAP geocoder
+// Geocoder constructs AMAP geocoder.
+// The AMAP web service answers in Chinese only, geo.WithLanguage merely sets the Accept-Language header.
 func Geocoder(key string, radius int, baseURLs ...string) geo.Geocoder {
 	if radius > 0 {
 		r = radius
@@ -65,6 +101,7 @@ func Geocoder(key string, radius int, baseURLs ...string) geo.Geocoder {
 		EndpointBuilder:       baseURL(getURL(key, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
 		ResponseUnmarshaler:   &geo.XMLUnmarshaler{},
+		Provider:              "amap",
 	}
 }
 
@@ -85,21 +122,119 @@ func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 	return strings.Replace(string(b), "*", "regeo", 1) + fmt.Sprintf("output=XML&location=%f,%f&radius=%d&extensions=all", l.Lng, l.Lat, r)
 }
 
+func (b baseURL) BatchSize() int { return batchSize }
+
+// BatchRequest https://restapi.amap.com/v3/geocode/geo?&output=XML&key=APPKEY&batch=true&address=ADDRESS1|ADDRESS2
+// '|' separates addresses, so it is replaced by a space within them.
+func (b baseURL) BatchRequest(addresses []string) geo.Request {
+	escaped := make([]string, len(addresses))
+	for i, a := range addresses {
+		escaped[i] = url.QueryEscape(strings.ReplaceAll(a, "|", " "))
+	}
+	return geo.Request{
+		Method: http.MethodGet,
+		URL:    strings.Replace(string(b), "*", "geo", 1) + "output=XML&batch=true&address=" + strings.Join(escaped, "|"),
+	}
+}
+
+// PlaceSearchURL https://restapi.amap.com/v3/place/around?output=XML&key=APPKEY&offset=10&keywords=KEYWORDS&location=121.49884033194,31.225696563611&radius=5000
+// Without q.Near it searches by keywords alone: https://restapi.amap.com/v3/place/text?output=XML&key=APPKEY&offset=10&keywords=KEYWORDS
+func (b baseURL) PlaceSearchURL(text string, q geo.PlaceQuery) string {
+	base := strings.Replace(string(b), "geocode/*", "place/*", 1)
+	if n := q.Near; n != nil {
+		return strings.Replace(base, "*", "around", 1) +
+			fmt.Sprintf("output=XML&offset=%d&keywords=%s&location=%f,%f&radius=%.0f", q.Limit, text, n.Lng, n.Lat, q.Radius)
+	}
+	return strings.Replace(base, "*", "text", 1) + fmt.Sprintf("output=XML&offset=%d&keywords=%s", q.Limit, text)
+}
+
+// err maps the infocode of a failed request onto a geo error, see
+// https://lbs.amap.com/api/webservice/guide/tools/info
+func (r *geocodeResponse) err() error {
+	if r.Status == statusOK {
+		return nil
+	}
+
+	var err error
+	switch c := r.Infocode; {
+	case c == 10003, c == 10004, c == 10014, c == 10015, c == 10019, c == 10020, c == 10021, c == 10044, c == 10045:
+		err = geo.ErrQuotaExceeded
+	case c >= 10000 && c < 20000:
+		err = geo.ErrUnauthorized
+	case c >= 20000 && c < 30000 && c != 20003:
+		err = geo.ErrInvalidRequest
+	default:
+		err = geo.ErrProviderUnavailable
+	}
+	return geo.StatusError(fmt.Sprintf("%d %s", r.Infocode, r.Info), err)
+}
+
 func (r *geocodeResponse) Location() (*geo.Location, error) {
-	var location = &geo.Location{}
+	results, err := r.Candidates()
+	if len(results) == 0 {
+		return nil, err
+	}
+	return &results[0].Location, nil
+}
+
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
 	if len(r.Geocodes) == 0 {
 		return nil, nil
 	}
-	if r.Status != statusOK {
-		return nil, fmt.Errorf("geocoding error: %v", r.Status)
+
+	results := make([]geo.Result, len(r.Geocodes))
+	for i, g := range r.Geocodes {
+		fmt.Sscanf(string(g.Location), "%f,%f", &results[i].Lng, &results[i].Lat)
+		results[i].FormattedAddress = g.FormattedAddress
+		results[i].PlaceType = g.Level
+		results[i].Precision = amapPrecisions[g.Level]
+	}
+	return results, nil
+}
+
+func (r *geocodeResponse) PlaceResults() ([]geo.Place, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+
+	places := make([]geo.Place, len(r.Pois))
+	for i, p := range r.Pois {
+		places[i] = geo.Place{
+			ID:       p.ID,
+			Name:     p.Name,
+			Category: p.Type[strings.LastIndex(p.Type, ";")+1:],
+			Address:  p.Address,
+			Distance: geo.ParseFloat(p.Distance),
+		}
+		fmt.Sscanf(p.Location, "%f,%f", &places[i].Location.Lng, &places[i].Location.Lat)
+	}
+	return places, nil
+}
+
+// BatchResults relies on AMAP answering a batch with one geocode per address, whose location is empty if it was not found
+func (r *geocodeResponse) BatchResults(n int) ([]geo.BatchResult, error) {
+	candidates, err := r.Candidates()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]geo.BatchResult, len(candidates))
+	for i := range candidates {
+		if r.Geocodes[i].Location == "" {
+			results[i].Err = geo.ErrNotFound
+			continue
+		}
+		results[i].Result = &candidates[i]
 	}
-	fmt.Sscanf(string(r.Geocodes[0].Location), "%f,%f", &location.Lng, &location.Lat)
-	return location, nil
+	return results, nil
 }
 
 func (r *geocodeResponse) Address() (*geo.Address, error) {
-	if r.Status != statusOK {
-		return nil, fmt.Errorf("reverse geocoding error: %v", r.Status)
+	if err := r.err(); err != nil {
+		return nil, err
 	}
 
 	addr := parseAmapResult(r)
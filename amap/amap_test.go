@@ -1,8 +1,10 @@
 package amap_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 
@@ -45,10 +47,56 @@ func TestReverseGeocodeWithNoResult(t *testing.T) {
 	geocoder := amap.Geocoder(key, 1000, ts.URL+"/")
 	addr, err := geocoder.ReverseGeocode(-37.81375, 164.97176)
 
-	assert.NoError(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
 
+func TestGeocodeBatch(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		resp.Write([]byte(responseBatch))
+	}))
+	defer ts.Close()
+
+	geocoder := amap.Geocoder(key, 1000, ts.URL+"/geocode/*?key=test&").(geo.BatchGeocoder)
+	results, err := geocoder.GeocodeBatch(context.Background(), []string{"北京市海淀区清河街道西二旗西路领秀新硅谷", "不存在|的地址"})
+	assert.NoError(t, err)
+	assert.Equal(t, "true", query.Get("batch"))
+	assert.Equal(t, "北京市海淀区清河街道西二旗西路领秀新硅谷|不存在 的地址", query.Get("address"))
+	assert.Len(t, results, 2)
+	assert.Equal(t, geo.Location{Lat: 40.055106, Lng: 116.309866}, results[0].Result.Location)
+	assert.ErrorIs(t, results[1].Err, geo.ErrNotFound)
+}
+
+func TestSearchPlaces(t *testing.T) {
+	var req *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, r *http.Request) {
+		req = r
+		resp.Write([]byte(responsePlaces))
+	}))
+	defer ts.Close()
+
+	geocoder := amap.Geocoder(key, 1000, ts.URL+"/geocode/*?key=test&").(geo.PlaceSearcher)
+	places, err := geocoder.SearchPlaces(context.Background(), geo.PlaceQuery{
+		Text:  "咖啡",
+		Near:  &geo.Location{Lat: 40.0551, Lng: 116.3098},
+		Limit: 5,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "/place/around", req.URL.Path)
+	assert.Equal(t, "咖啡", req.URL.Query().Get("keywords"))
+	assert.Equal(t, "116.309800,40.055100", req.URL.Query().Get("location"))
+	assert.Equal(t, []geo.Place{{
+		ID:       "B0FFG4Y3FH",
+		Name:     "星巴克(领秀新硅谷店)",
+		Category: "星巴克咖啡",
+		Location: geo.Location{Lat: 40.055734, Lng: 116.310468},
+		Address:  "西二旗西路2号院",
+		Distance: 88,
+	}}, places)
+}
+
 func testServer(response string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		resp.Write([]byte(response))
@@ -56,7 +104,9 @@ func testServer(response string) *httptest.Server {
 }
 
 const (
-	response1 = `<?xml version="1.0" encoding="UTF-8"?><response><status>1</status><info>OK</info><infocode>10000</infocode><count>1</count><geocodes type="list"><geocode><formatted_address>北京市海淀区清河街道西二旗西路领秀新硅谷</formatted_address><country>中国</country><province>北京市</province><citycode>010</citycode><city>北京市</city><district>海淀区</district><township></township><neighborhood><name></name><type></type></neighborhood><building><name></name><type></type></building><adcode>110108</adcode><street>西二旗西路</street><number></number><location>116.309866,40.055106</location><level>住宅区</level></geocode></geocodes></response>`
-	response2 = `<?xml version="1.0" encoding="UTF-8"?><response><status>1</status><info>OK</info><infocode>10000</infocode><regeocode><formatted_address>北京市海淀区上地街道树村郊野公园</formatted_address><addressComponent><country>中国</country><province>北京市</province><city></city><citycode>010</citycode><district>海淀区</district><adcode>110108</adcode><township>上地街道</township><towncode>110108022000</towncode><neighborhood><name></name><type></type></neighborhood><building><name>树村郊野公园</name><type>风景名胜;公园广场;公园</type></building><streetNumber><street>马连洼北路</street><number>29号</number><location>116.299587,40.034620</location><direction>北</direction><distance>147.306</distance></streetNumber><businessAreas type="list"><businessArea><location>116.303276,40.035542</location><name>上地</name><id>110108</id></businessArea><businessArea><location>116.256057,40.054273</location><name>西北   </name><id>110108</id></businessArea><businessArea><location>116.281156,40.028654</location><name>马连洼</name><id>110108</id></businessArea></businessAreas></addressComponent></regeocode></response>`
-	response3 = `<?xml version="1.0" encoding="UTF-8"?><response><status>1</status><info>OK</info><infocode>10000</infocode><regeocode><formatted_address></formatted_address><addressComponent><country></country><province></province><city></city><citycode></citycode><district></district><adcode></adcode><township></township><towncode></towncode></addressComponent><pois type="list"/><roads type="list"/><roadinters type="list"/><aois type="list"/></regeocode></response>`
+	responseBatch  = `<?xml version="1.0" encoding="UTF-8"?><response><status>1</status><info>OK</info><infocode>10000</infocode><count>2</count><geocodes type="list"><geocode><formatted_address>北京市海淀区清河街道西二旗西路领秀新硅谷</formatted_address><country>中国</country><province>北京市</province><citycode>010</citycode><city>北京市</city><district>海淀区</district><adcode>110108</adcode><street>西二旗西路</street><number></number><location>116.309866,40.055106</location><level>住宅区</level></geocode><geocode><formatted_address></formatted_address><country></country><province></province><citycode></citycode><city></city><district></district><adcode></adcode><street></street><number></number><location></location><level></level></geocode></geocodes></response>`
+	response1      = `<?xml version="1.0" encoding="UTF-8"?><response><status>1</status><info>OK</info><infocode>10000</infocode><count>1</count><geocodes type="list"><geocode><formatted_address>北京市海淀区清河街道西二旗西路领秀新硅谷</formatted_address><country>中国</country><province>北京市</province><citycode>010</citycode><city>北京市</city><district>海淀区</district><township></township><neighborhood><name></name><type></type></neighborhood><building><name></name><type></type></building><adcode>110108</adcode><street>西二旗西路</street><number></number><location>116.309866,40.055106</location><level>住宅区</level></geocode></geocodes></response>`
+	response2      = `<?xml version="1.0" encoding="UTF-8"?><response><status>1</status><info>OK</info><infocode>10000</infocode><regeocode><formatted_address>北京市海淀区上地街道树村郊野公园</formatted_address><addressComponent><country>中国</country><province>北京市</province><city></city><citycode>010</citycode><district>海淀区</district><adcode>110108</adcode><township>上地街道</township><towncode>110108022000</towncode><neighborhood><name></name><type></type></neighborhood><building><name>树村郊野公园</name><type>风景名胜;公园广场;公园</type></building><streetNumber><street>马连洼北路</street><number>29号</number><location>116.299587,40.034620</location><direction>北</direction><distance>147.306</distance></streetNumber><businessAreas type="list"><businessArea><location>116.303276,40.035542</location><name>上地</name><id>110108</id></businessArea><businessArea><location>116.256057,40.054273</location><name>西北   </name><id>110108</id></businessArea><businessArea><location>116.281156,40.028654</location><name>马连洼</name><id>110108</id></businessArea></businessAreas></addressComponent></regeocode></response>`
+	response3      = `<?xml version="1.0" encoding="UTF-8"?><response><status>1</status><info>OK</info><infocode>10000</infocode><regeocode><formatted_address></formatted_address><addressComponent><country></country><province></province><city></city><citycode></citycode><district></district><adcode></adcode><township></township><towncode></towncode></addressComponent><pois type="list"/><roads type="list"/><roadinters type="list"/><aois type="list"/></regeocode></response>`
+	responsePlaces = `<?xml version="1.0" encoding="UTF-8"?><response><status>1</status><count>1</count><info>OK</info><infocode>10000</infocode><pois type="list"><poi><id>B0FFG4Y3FH</id><parent></parent><name>星巴克(领秀新硅谷店)</name><type>餐饮服务;咖啡厅;星巴克咖啡</type><typecode>050501</typecode><address>西二旗西路2号院</address><location>116.310468,40.055734</location><distance>88</distance></poi></pois></response>`
 )
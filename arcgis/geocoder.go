@@ -2,7 +2,10 @@
 package arcgis
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 
 	geo "github.com/codingsince1985/geo-golang"
@@ -11,13 +14,20 @@ import (
 type (
 	baseURL         string
 	geocodeResponse struct {
-		Candidates []struct {
+		AddressCandidates []struct {
 			Address  string
 			Location struct {
 				X float64
 				Y float64
 			}
-		}
+			Score      float64
+			Attributes struct {
+				AddrType string `json:"Addr_type"`
+			}
+			Extent struct {
+				XMin, YMin, XMax, YMax float64
+			}
+		} `json:"candidates"`
 
 		ReverseAddress struct {
 			MatchAddr    string `json:"Match_addr"`
@@ -32,14 +42,55 @@ type (
 			Postal       string
 			CountryCode  string
 		} `json:"address"`
+
+		SuggestResults []struct {
+			Text         string
+			MagicKey     string
+			IsCollection bool
+		} `json:"suggestions"`
+
+		// Batch geocoding response, in no particular order
+		Locations []struct {
+			Address  string
+			Location struct {
+				X coordinate
+				Y coordinate
+			}
+			Score      float64
+			Attributes struct {
+				ResultID int
+				Status   string // M for matched, T for tied, U for unmatched
+				AddrType string `json:"Addr_type"`
+			}
+		}
+
+		Error struct {
+			Code    int
+			Message string
+			Details []string
+		}
 	}
 )
 
+// coordinate is a number, which is sent as the string "NaN" for unmatched addresses
+type coordinate float64
+
+func (c *coordinate) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		return nil
+	}
+	return json.Unmarshal(data, (*float64)(c))
+}
+
+// batchSize is the most addresses the World Geocoding Service takes in a geocodeAddresses request
+const batchSize = 1000
+
 // Geocoder constructs ArcGIS geocoder
 func Geocoder(token string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getUrl(token, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "arcgis",
 	}
 }
 
@@ -55,29 +106,229 @@ func getUrl(token string, baseURLs ...string) string {
 	return url
 }
 
-func (b baseURL) GeocodeURL(address string) string {
-	params := fmt.Sprintf("findAddressCandidates?f=json&maxLocations=%d&SingleLine=%s", 1, address)
+func (b baseURL) GeocodeURL(address string) string { return b.GeocodeAllURL(address, 1) }
+
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	params := fmt.Sprintf("findAddressCandidates?f=json&outFields=Addr_type&maxLocations=%d&SingleLine=%s", limit, address)
+	return strings.Replace(string(b), "*", params, 1)
+}
+
+// StructuredGeocodeURL passes each address field as its own findAddressCandidates parameter
+func (b baseURL) StructuredGeocodeURL(a geo.Address) string {
+	v := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	set("Address", strings.TrimSpace(a.HouseNumber+" "+a.Street))
+	set("Neighborhood", a.Suburb)
+	set("City", a.City)
+	set("Subregion", a.County)
+	set("Region", a.State)
+	set("Postal", a.Postcode)
+	set("CountryCode", a.CountryCode)
+	if a.CountryCode == "" {
+		set("CountryCode", a.Country)
+	}
+	params := "findAddressCandidates?f=json&outFields=Addr_type&maxLocations=1&" + v.Encode()
+	return strings.Replace(string(b), "*", params, 1)
+}
+
+// SuggestURL uses the suggest operation, whose suggestions carry a magicKey rather than a location
+func (b baseURL) SuggestURL(partial string, opts geo.SuggestOptions) string {
+	params := fmt.Sprintf("suggest?f=json&maxSuggestions=%d&text=%s", opts.Limit, partial)
+	if p := opts.Proximity; p != nil {
+		params += fmt.Sprintf("&location=%f,%f", p.Lng, p.Lat)
+	}
+	return strings.Replace(string(b), "*", params, 1)
+}
+
+// ResolveURL finds the candidate of a suggestion by its text along with its magicKey
+func (b baseURL) ResolveURL(s geo.Suggestion) string {
+	params := "findAddressCandidates?f=json&outFields=Addr_type&maxLocations=1&" +
+		url.Values{"SingleLine": {s.Text}, "magicKey": {s.ID}}.Encode()
 	return strings.Replace(string(b), "*", params, 1)
 }
 
+func (b baseURL) BatchSize() int { return batchSize }
+
+// BatchRequest posts addresses as geocodeAddresses records, identified by their position in addresses from 1 on.
+// The World Geocoding Service requires a token for batch geocoding.
+func (b baseURL) BatchRequest(addresses []string) geo.Request {
+	type record struct {
+		Attributes struct {
+			ObjectID   int    `json:"OBJECTID"`
+			SingleLine string `json:"SingleLine"`
+		} `json:"attributes"`
+	}
+	records := make([]record, len(addresses))
+	for i, a := range addresses {
+		records[i].Attributes.ObjectID = i + 1
+		records[i].Attributes.SingleLine = a
+	}
+	data, _ := json.Marshal(map[string][]record{"records": records})
+
+	return geo.Request{
+		Method:      http.MethodPost,
+		URL:         strings.Replace(string(b), "*", "geocodeAddresses?f=json", 1),
+		ContentType: "application/x-www-form-urlencoded",
+		Body:        []byte(url.Values{"addresses": {string(data)}}.Encode()),
+	}
+}
+
+func (b baseURL) LanguageParams(language string) url.Values {
+	return url.Values{"langCode": {language}}
+}
+
+// FilterURL maps f onto countryCode, searchExtent and location.
+// ArcGIS can only restrict to bounds, not bias toward them.
+func (b baseURL) FilterURL(rawURL string, f geo.Filter) (string, error) {
+	if f.Bounds != nil && !f.RestrictToBounds {
+		return "", geo.UnsupportedError("bounds bias")
+	}
+
+	params := url.Values{}
+	if len(f.Countries) > 0 {
+		params.Set("countryCode", strings.ToUpper(strings.Join(f.Countries, ",")))
+	}
+	if bb := f.Bounds; bb != nil {
+		params.Set("searchExtent", fmt.Sprintf("%f,%f,%f,%f", bb.West, bb.South, bb.East, bb.North))
+	}
+	if p := f.Proximity; p != nil {
+		params.Set("location", fmt.Sprintf("%f,%f", p.Lng, p.Lat))
+	}
+	return geo.SetParams(rawURL, params), nil
+}
+
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 	params := fmt.Sprintf("reverseGeocode?f=json&location=%f,%f", l.Lng, l.Lat)
 	return strings.Replace(string(b), "*", params, 1)
 }
 
+// err maps an error reported in the body of a response onto a geo error
+func (r *geocodeResponse) err() error {
+	var err error
+	switch e := r.Error; {
+	case e.Code == 0:
+		return nil
+	case e.Code == 498 || e.Code == 499:
+		// invalid or missing token
+		err = geo.ErrUnauthorized
+	case unableToFind(e.Message, e.Details):
+		err = geo.ErrNotFound
+	case e.Code >= 400:
+		err = geo.HTTPStatusError(e.Code)
+	default:
+		err = geo.ErrProviderUnavailable
+	}
+	return geo.StatusError(fmt.Sprintf("%d %s", r.Error.Code, r.Error.Message), err)
+}
+
+// unableToFind reports whether ArcGIS said it couldn't find an address, usually in the details of a 400 error
+func unableToFind(message string, details []string) bool {
+	for _, m := range append([]string{message}, details...) {
+		if strings.HasPrefix(m, "Unable to find") {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *geocodeResponse) Location() (*geo.Location, error) {
-	if len(r.Candidates) == 0 {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+	if len(r.AddressCandidates) == 0 {
 		return nil, nil
 	}
 
-	g := r.Candidates[0].Location
+	g := r.AddressCandidates[0].Location
 	return &geo.Location{
 		Lat: g.Y,
 		Lng: g.X,
 	}, nil
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+	results := make([]geo.Result, 0, len(r.AddressCandidates))
+	for _, c := range r.AddressCandidates {
+		result := geo.Result{
+			Location:         geo.Location{Lat: c.Location.Y, Lng: c.Location.X},
+			FormattedAddress: c.Address,
+			PlaceType:        c.Attributes.AddrType,
+			Precision:        arcgisPrecision(c.Attributes.AddrType),
+			Confidence:       c.Score / 100,
+		}
+		if e := c.Extent; e.XMin != 0 || e.XMax != 0 {
+			result.BoundingBox = &geo.BoundingBox{South: e.YMin, West: e.XMin, North: e.YMax, East: e.XMax}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Suggestions skips collections, which suggest a category of places rather than an address
+func (r *geocodeResponse) Suggestions() ([]geo.Suggestion, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+	suggestions := make([]geo.Suggestion, 0, len(r.SuggestResults))
+	for _, s := range r.SuggestResults {
+		if !s.IsCollection {
+			suggestions = append(suggestions, geo.Suggestion{ID: s.MagicKey, Text: s.Text})
+		}
+	}
+	return suggestions, nil
+}
+
+func (r *geocodeResponse) BatchResults(n int) ([]geo.BatchResult, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]geo.BatchResult, n)
+	for i := range results {
+		results[i].Err = geo.ErrNotFound
+	}
+	for _, l := range r.Locations {
+		i := l.Attributes.ResultID - 1
+		if i < 0 || i >= n || l.Attributes.Status == "U" {
+			continue
+		}
+		results[i] = geo.BatchResult{Result: &geo.Result{
+			Location:         geo.Location{Lat: float64(l.Location.Y), Lng: float64(l.Location.X)},
+			FormattedAddress: l.Address,
+			PlaceType:        l.Attributes.AddrType,
+			Precision:        arcgisPrecision(l.Attributes.AddrType),
+			Confidence:       l.Score / 100,
+		}}
+	}
+	return results, nil
+}
+
+func arcgisPrecision(addrType string) geo.Precision {
+	switch addrType {
+	case "PointAddress", "Subaddress", "POI":
+		return geo.PrecisionRooftop
+	case "StreetAddress", "StreetAddressExt", "StreetInt", "StreetBetween", "StreetName", "DistanceMarker":
+		return geo.PrecisionStreet
+	case "Locality", "Postal", "PostalExt", "PostalLoc":
+		return geo.PrecisionCity
+	}
+	return geo.PrecisionUnknown
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+	if r.ReverseAddress.MatchAddr == "" {
+		return nil, nil
+	}
 	addr := &geo.Address{
 		FormattedAddress: r.ReverseAddress.MatchAddr,
 		Street:           r.ReverseAddress.Address,
@@ -1,10 +1,13 @@
 package arcgis
 
 import (
+	"context"
+	"errors"
 	"math"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	geo "github.com/codingsince1985/geo-golang"
@@ -61,6 +64,78 @@ func TestReverseGeocode(t *testing.T) {
 	}
 }
 
+func TestReverseGeocodeNotFound(t *testing.T) {
+	for _, response := range []string{reverseUnableToFindResp, `{"address":{},"location":{}}`} {
+		ts := testServer(response)
+		addr, err := Geocoder(token, ts.URL).ReverseGeocode(0, 0)
+		ts.Close()
+		if !errors.Is(err, geo.ErrNotFound) || addr != nil {
+			t.Fatalf("Got: %v, %v\tExpected: %v\n", addr, err, geo.ErrNotFound)
+		}
+	}
+}
+
+func TestSuggestAndResolve(t *testing.T) {
+	var magicKey string
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if strings.HasSuffix(req.URL.Path, "/suggest") {
+			resp.Write([]byte(suggestResp))
+			return
+		}
+		magicKey = req.URL.Query().Get("magicKey")
+		resp.Write([]byte(geocodeResp))
+	}))
+	defer ts.Close()
+
+	geocoder := Geocoder(token, ts.URL+"/*").(geo.Suggester)
+	suggestions, err := geocoder.Suggest(context.Background(), "380 New York", geo.SuggestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 1 || suggestions[0].Text != "380 New York St, Redlands, CA, 92373, USA" || suggestions[0].Result != nil {
+		t.Fatalf("Got: %v\n", suggestions)
+	}
+
+	result, err := geocoder.Resolve(context.Background(), suggestions[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if magicKey != suggestions[0].ID {
+		t.Fatalf("Got: %s\tExpected: %s\n", magicKey, suggestions[0].ID)
+	}
+	if math.Abs(result.Lat-34.056488119308924) > eps {
+		t.Fatalf("Got: %v\n", result)
+	}
+}
+
+func TestGeocodeBatch(t *testing.T) {
+	var addresses string
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		addresses = req.FormValue("addresses")
+		resp.Write([]byte(batchResp))
+	}))
+	defer ts.Close()
+
+	geocoder := Geocoder(token, ts.URL+"/*").(geo.BatchGeocoder)
+	results, err := geocoder.GeocodeBatch(context.Background(), []string{"nowhere", "380 New York, Redlands, CA 92373, USA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(addresses, `{"OBJECTID":2,"SingleLine":"380 New York, Redlands, CA 92373, USA"}`) {
+		t.Fatalf("Got: %s\n", addresses)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Got: %d results\tExpected: 2\n", len(results))
+	}
+	if !errors.Is(results[0].Err, geo.ErrNotFound) {
+		t.Fatalf("Got: %v\tExpected: %v\n", results[0].Err, geo.ErrNotFound)
+	}
+	if results[1].Err != nil || math.Abs(results[1].Result.Lat-34.056488119308924) > eps || results[1].Result.Precision != geo.PrecisionRooftop {
+		t.Fatalf("Got: %+v\n", results[1])
+	}
+}
+
 func testServer(response string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		resp.Write([]byte(response))
@@ -70,6 +145,28 @@ func testServer(response string) *httptest.Server {
 const (
 	eps = 1.0e-5
 
+	reverseUnableToFindResp = `{"error":{"code":400,"message":"Cannot perform query. Invalid query parameters.","details":["Unable to find address for the specified location."]}}`
+
+	batchResp = `{"spatialReference":{"wkid":4326,"latestWkid":4326},"locations":[
+		{"address":"380 New York St, Redlands, California, 92373","location":{"x":-117.1956703176181,"y":34.056488119308924},"score":100,"attributes":{"ResultID":2,"Status":"M","Addr_type":"PointAddress"}},
+		{"address":"","location":{"x":"NaN","y":"NaN"},"score":0,"attributes":{"ResultID":1,"Status":"U","Addr_type":""}}
+	]}`
+
+	suggestResp = `{
+ "suggestions": [
+  {
+   "text": "380 New York St, Redlands, CA, 92373, USA",
+   "magicKey": "dHA9MCNsb2M9MjM0NjIzNDUjbG5nPTMz",
+   "isCollection": false
+  },
+  {
+   "text": "Coffee Shop",
+   "magicKey": "dHA9MyNsb2M9MjM0NjIzNDUjbG5nPTMz",
+   "isCollection": true
+  }
+ ]
+}`
+
 	geocodeResp = `{
  "spatialReference": {
   "wkid": 4326,
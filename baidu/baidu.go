@@ -2,8 +2,11 @@ package baidu
 
 import (
 	"fmt"
-	"github.com/codingsince1985/geo-golang"
+	"net/url"
+	"slices"
 	"strings"
+
+	"github.com/codingsince1985/geo-golang"
 )
 
 var (
@@ -64,12 +67,47 @@ type (
 				Lat float64 `json:"lat"`
 				Lng float64 `json:"lng"`
 			} `json:"location"`
+			Precise            int           `json:"precise"`
+			Confidence         int           `json:"confidence"`
+			Comprehension      int           `json:"comprehension"`
+			Level              string        `json:"level"`
 			PoiRegions         []interface{} `json:"poiRegions"`
-			Pois               []interface{} `json:"pois"`
+			Pois               []poi         `json:"pois"`
 			Roads              []interface{} `json:"roads"`
 			SematicDescription string        `json:"sematic_description"`
 		} `json:"result"`
-		Status int `json:"status"`
+		// Place search response
+		Results []struct {
+			Name     string `json:"name"`
+			UID      string `json:"uid"`
+			Address  string `json:"address"`
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+			DetailInfo struct {
+				Tag      string  `json:"tag"`
+				Distance float64 `json:"distance"`
+			} `json:"detail_info"`
+		} `json:"results"`
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	}
+
+	// poi is a point of interest near a reverse geocoded location
+	poi struct {
+		UID       string `json:"uid"`
+		Name      string `json:"name"`
+		Addr      string `json:"addr"`
+		PoiType   string `json:"poiType"`
+		Tag       string `json:"tag"`
+		Tel       string `json:"tel"`
+		Direction string `json:"direction"`
+		Distance  string `json:"distance"`
+		Point     struct {
+			X float64 `json:"x"` // longitude
+			Y float64 `json:"y"` // latitude
+		} `json:"point"`
 	}
 )
 
@@ -77,7 +115,6 @@ const (
 	statusOK = 0
 )
 
-var lang string
 var coordtype string
 
 // Geocoder constructs Baidu geocoder
@@ -90,6 +127,7 @@ var coordtype string
 // "fi", "da", "ja", "te", "pt-PT", "ml", "ko", "kn", "sk", "zh-CN", "pl", "uk", "sl", "mr", "local"
 //
 // "local" is a special argument for language specifying, which means the language it uses depends on your location.
+// The language can be changed later with geo.WithLanguage.
 //
 // coordtype:
 // According to league reasons, Chinese geo service providers uses encrypted coordinate system (GCJ02ll, BD09ll, BD09mc,
@@ -100,13 +138,6 @@ var coordtype string
 // You can use https://api.map.baidu.com/geoconv/v1/?coords=LONGITUDE,LATITUDE&from=1&to=5&ak=AK to convert from WGS84ll
 // to BD09ll coordination. API document: https://lbsyun.baidu.com/index.php?title=webapi/guide/changeposition
 func Geocoder(apiKey string, language string, coordtype string, baseURLs ...string) geo.Geocoder {
-	if lang != "" {
-		for _, item := range languageList {
-			if language == item {
-				lang = fmt.Sprintf("&language=%s", language)
-			}
-		}
-	}
 	switch coordtype {
 	case "bd09ll":
 		coordtype = "bd09ll"
@@ -123,6 +154,8 @@ func Geocoder(apiKey string, language string, coordtype string, baseURLs ...stri
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getURL(apiKey, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "baidu",
+		Options:               geo.Options{Language: language},
 	}
 }
 
@@ -140,15 +173,31 @@ func (b baseURL) GeocodeURL(address string) string {
 
 // ReverseGeocodeURL https://api.map.baidu.com/reverse_geocoding/v3/?ak=APPKEY&output=json&&coordtype=wgs84ll&location=31.225696563611,121.49884033194
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
-	return strings.Replace(string(b), "*", "reverse_geocoding", 1) + fmt.Sprintf("output=json&coordtype=%s&location=%f,%f", coordtype, l.Lat, l.Lng) + lang
+	return strings.Replace(string(b), "*", "reverse_geocoding", 1) + fmt.Sprintf("output=json&coordtype=%s&location=%f,%f", coordtype, l.Lat, l.Lng)
+}
+
+// PlaceSearchURL https://api.map.baidu.com/place/v2/search?ak=APPKEY&output=json&scope=2&page_size=10&query=QUERY&location=31.225696563611,121.49884033194&radius=5000
+// Without q.Near it searches the whole country. Coordinates are in bd09ll.
+func (b baseURL) PlaceSearchURL(text string, q geo.PlaceQuery) string {
+	u := strings.Replace(string(b), "*/v3/", "place/v2/search", 1) + fmt.Sprintf("output=json&scope=2&page_size=%d&query=%s", q.Limit, text)
+	if n := q.Near; n != nil {
+		return u + fmt.Sprintf("&location=%f,%f&radius=%.0f", n.Lat, n.Lng, q.Radius)
+	}
+	return u + "&region=" + url.QueryEscape("全国")
+}
+
+// LanguageParams asks for language if it is one of languageList
+func (b baseURL) LanguageParams(language string) url.Values {
+	if !slices.Contains(languageList, language) {
+		return nil
+	}
+	return url.Values{"language": {language}}
 }
 
 func (r *geocodeResponse) Location() (*geo.Location, error) {
 	var location = &geo.Location{}
-	if r.Status == 1 {
-		return nil, nil
-	} else if r.Status != statusOK {
-		return nil, fmt.Errorf("geocoding error: %v", r.Status)
+	if err := r.err(); err != nil {
+		return nil, err
 	}
 	location.Lat = r.Result.Location.Lat
 	location.Lng = r.Result.Location.Lng
@@ -156,11 +205,84 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	loc, err := r.Location()
+	if loc == nil {
+		return nil, err
+	}
+	return []geo.Result{{
+		Location:  *loc,
+		PlaceType: r.Result.Level,
+		Precision: baiduPrecision(r.Result.Level, r.Result.Precise),
+		// confidence rates from 0 to 100 the absolute accuracy of the point, the higher the smaller its error in metres.
+		// How well the address was understood is comprehension, which isn't used.
+		Confidence: float64(r.Result.Confidence) / 100,
+	}}, nil
+}
+
+// err maps the status of a failed request onto a geo error, see
+// https://lbsyun.baidu.com/index.php?title=webapi/appendix
+func (r *geocodeResponse) err() error {
+	var err error
+	switch s := r.Status; {
+	case s == statusOK:
+		return nil
+	case s == 1:
+		// no result is reported as an internal error
+		err = geo.ErrNotFound
+	case s == 2:
+		err = geo.ErrInvalidRequest
+	case s == 4, s >= 300 && s < 500:
+		err = geo.ErrQuotaExceeded
+	case s == 3, s == 5, s >= 100 && s < 300:
+		err = geo.ErrUnauthorized
+	default:
+		err = geo.ErrProviderUnavailable
+	}
+	return geo.StatusError(strings.TrimSpace(fmt.Sprintf("%d %s", r.Status, r.Message)), err)
+}
+
+func (r *geocodeResponse) PlaceResults() ([]geo.Place, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+
+	places := make([]geo.Place, len(r.Results))
+	for i, res := range r.Results {
+		places[i] = geo.Place{
+			ID:       res.UID,
+			Name:     res.Name,
+			Category: res.DetailInfo.Tag[strings.LastIndex(res.DetailInfo.Tag, ";")+1:],
+			Location: geo.Location{Lat: res.Location.Lat, Lng: res.Location.Lng},
+			Address:  res.Address,
+			Distance: res.DetailInfo.Distance,
+		}
+	}
+	return places, nil
+}
+
+func baiduPrecision(level string, precise int) geo.Precision {
+	switch level {
+	case "门址", "POI", "门牌号":
+		if precise == 1 {
+			return geo.PrecisionRooftop
+		}
+		return geo.PrecisionStreet
+	case "道路", "道路交叉路口":
+		return geo.PrecisionStreet
+	case "城市", "区县", "乡镇", "村庄", "商圈":
+		return geo.PrecisionCity
+	case "省份":
+		return geo.PrecisionRegion
+	case "国家":
+		return geo.PrecisionCountry
+	}
+	return geo.PrecisionUnknown
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
-	if r.Status == 1 {
-		return nil, nil
-	} else if r.Status != statusOK {
-		return nil, fmt.Errorf("reverse geocoding error: %v", r.Status)
+	if err := r.err(); err != nil {
+		return nil, err
 	}
 
 	addr := parseBaiduResult(r)
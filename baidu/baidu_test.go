@@ -1,8 +1,10 @@
 package baidu_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
@@ -25,6 +27,24 @@ func TestGeocode(t *testing.T) {
 	assert.Equal(t, geo.Location{Lat: 40.05703033345938, Lng: 116.3084202915042}, *location)
 }
 
+func TestGeocodeAllPrecision(t *testing.T) {
+	for response, precision := range map[string]geo.Precision{
+		response1: geo.PrecisionRooftop,
+		strings.Replace(response1, "门址", "UNKNOWN", 1): geo.PrecisionUnknown,
+		strings.Replace(response1, "门址", "NoClass", 1): geo.PrecisionUnknown,
+	} {
+		ts := testServer(response)
+
+		geocoder := baidu.Geocoder(key, "en", "bd09ll", ts.URL+"/")
+		results, err := geocoder.(geo.MultiGeocoder).GeocodeAll("60 Collins St, Melbourne VIC", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, precision, results[0].Precision)
+		assert.Equal(t, 0.8, results[0].Confidence)
+		ts.Close()
+	}
+}
+
 func TestReverseGeocode(t *testing.T) {
 	ts := testServer(response2)
 	defer ts.Close()
@@ -37,6 +57,28 @@ func TestReverseGeocode(t *testing.T) {
 	assert.True(t, address.City == "Beijing")
 }
 
+func TestReverseGeocodeWithLanguage(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		resp.Write([]byte(response2))
+	}))
+	defer ts.Close()
+
+	geocoder := baidu.Geocoder(key, "en", "bd09ll", ts.URL+"/?")
+	_, err := geocoder.ReverseGeocode(40.03333340036988, 116.29999999999993)
+	assert.NoError(t, err)
+	assert.Equal(t, "en", query.Get("language"))
+
+	_, err = geo.Configure(geocoder, geo.WithLanguage("fr")).ReverseGeocode(40.03333340036988, 116.29999999999993)
+	assert.NoError(t, err)
+	assert.Equal(t, "fr", query.Get("language"))
+
+	_, err = geo.Configure(geocoder, geo.WithLanguage("xx")).ReverseGeocode(40.03333340036988, 116.29999999999993)
+	assert.NoError(t, err)
+	assert.False(t, query.Has("language"))
+}
+
 func TestReverseGeocodeWithNoResult(t *testing.T) {
 	ts := testServer(response3)
 	defer ts.Close()
@@ -44,10 +86,31 @@ func TestReverseGeocodeWithNoResult(t *testing.T) {
 	geocoder := baidu.Geocoder(key, "en", "bd09ll", ts.URL+"/")
 	addr, err := geocoder.ReverseGeocode(-37.81375, 164.97176)
 
-	assert.NoError(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
 
+func TestSearchPlaces(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		resp.Write([]byte(responsePlaces))
+	}))
+	defer ts.Close()
+
+	geocoder := baidu.Geocoder(key, "zh-CN", "bd09ll", ts.URL+"/*/v3/?ak=test&").(geo.PlaceSearcher)
+	places, err := geocoder.SearchPlaces(context.Background(), geo.PlaceQuery{Text: "咖啡"})
+	assert.NoError(t, err)
+	assert.Equal(t, "咖啡", query.Get("query"))
+	assert.Equal(t, "全国", query.Get("region"))
+	assert.Equal(t, "10", query.Get("page_size"))
+	assert.Len(t, places, 1)
+	assert.Equal(t, "星巴克(上地店)", places[0].Name)
+	assert.Equal(t, "咖啡厅", places[0].Category)
+	assert.Equal(t, geo.Location{Lat: 40.036231, Lng: 116.310342}, places[0].Location)
+	assert.Zero(t, places[0].Distance)
+}
+
 func testServer(response string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		resp.Write([]byte(response))
@@ -55,7 +118,23 @@ func testServer(response string) *httptest.Server {
 }
 
 const (
-	response1 = `{"status":0,"result":{"location":{"lng":116.3084202915042,"lat":40.05703033345938},"precise":1,"confidence":80,"comprehension":100,"level":"门址"}}`
-	response2 = `{"status":0,"result":{"location":{"lng":116.29999999999993,"lat":40.03333340036988},"formatted_address":"43号 农大南路, Haidian, Beijing, China","business":"马连洼,上地","addressComponent":{"country":"China","country_code":0,"country_code_iso":"CHN","country_code_iso2":"CN","province":"Beijing","city":"Beijing","city_level":2,"district":"Haidian","town":"","town_code":"","adcode":"110108","street":"农大南路","street_number":"43号","direction":"附近","distance":"26"},"pois":[],"roads":[],"poiRegions":[],"sematic_description":"","cityCode":131}}`
-	response3 = `{"status":0,"result":{"location":{"lng":164.97175999999986,"lat":-37.81375002268602},"formatted_address":"","business":"","addressComponent":{"country":"","country_code":-1,"country_code_iso":"","country_code_iso2":"","province":"","city":"","city_level":2,"district":"","town":"","town_code":"","adcode":"0","street":"","street_number":"","direction":"","distance":""},"pois":[],"roads":[],"poiRegions":[],"sematic_description":"","cityCode":0}}`
+	response1      = `{"status":0,"result":{"location":{"lng":116.3084202915042,"lat":40.05703033345938},"precise":1,"confidence":80,"comprehension":100,"level":"门址"}}`
+	response2      = `{"status":0,"result":{"location":{"lng":116.29999999999993,"lat":40.03333340036988},"formatted_address":"43号 农大南路, Haidian, Beijing, China","business":"马连洼,上地","addressComponent":{"country":"China","country_code":0,"country_code_iso":"CHN","country_code_iso2":"CN","province":"Beijing","city":"Beijing","city_level":2,"district":"Haidian","town":"","town_code":"","adcode":"110108","street":"农大南路","street_number":"43号","direction":"附近","distance":"26"},"pois":[],"roads":[],"poiRegions":[],"sematic_description":"","cityCode":131}}`
+	response3      = `{"status":0,"result":{"location":{"lng":164.97175999999986,"lat":-37.81375002268602},"formatted_address":"","business":"","addressComponent":{"country":"","country_code":-1,"country_code_iso":"","country_code_iso2":"","province":"","city":"","city_level":2,"district":"","town":"","town_code":"","adcode":"0","street":"","street_number":"","direction":"","distance":""},"pois":[],"roads":[],"poiRegions":[],"sematic_description":"","cityCode":0}}`
+	responsePlaces = `{
+		"status": 0,
+		"message": "ok",
+		"results": [
+			{
+				"name": "星巴克(上地店)",
+				"location": {"lat": 40.036231, "lng": 116.310342},
+				"address": "北京市海淀区上地信息路甲9号",
+				"province": "北京市",
+				"city": "北京市",
+				"area": "海淀区",
+				"uid": "6b0e4a5d5f4e4f5cd0d8bc7f",
+				"detail_info": {"tag": "美食;咖啡厅", "type": "cater"}
+			}
+		]
+	}`
 )
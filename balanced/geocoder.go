@@ -0,0 +1,428 @@
+// Package balanced spreads lookups across geocoders, keeping each of them within the budget of its free quota
+package balanced
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+// Strategy decides which geocoder of a Balancer to ask first
+type Strategy int
+
+const (
+	// RoundRobin asks each geocoder in turn
+	RoundRobin Strategy = iota
+	// Weighted asks each geocoder in proportion to its weight, interleaving them
+	Weighted
+	// LeastUsed asks the geocoder which used the smallest share of its budget, then the fewest requests today
+	LeastUsed
+)
+
+// Budget is how many requests a provider may be sent a day and a month, 0 for no limit
+type Budget struct {
+	Daily, Monthly int
+}
+
+// Member is a geocoder of a Balancer
+type Member struct {
+	Geocoder geo.Geocoder
+	// Name identifies the member in usage and errors, the provider of a geo.HTTPGeocoder by default.
+	// It must not change across restarts for the usage saved to be found again.
+	Name string
+	// Weight is the share of requests the member gets with the Weighted strategy, 1 by default
+	Weight int
+	Budget Budget
+}
+
+// DefaultSaveInterval is how often at most the usage is saved to the store unless told otherwise
+const DefaultSaveInterval = time.Second
+
+// Option configures a Balancer
+type Option func(*Balancer)
+
+// WithStore saves the usage of the members to s and resumes from it, so that restarts don't reset it.
+// Usage is saved by a request at most every save interval, the requests since the last save are only saved by
+// the next one or Flush: flush the Balancer before exiting.
+func WithStore(s Store) Option { return func(b *Balancer) { b.store = s } }
+
+// WithSaveInterval sets how often at most the usage is saved to the store, DefaultSaveInterval by default.
+// 0 saves it after every request.
+func WithSaveInterval(d time.Duration) Option { return func(b *Balancer) { b.saveInterval = d } }
+
+// WithLocation sets the time zone in which days and months of budgets start, UTC by default
+func WithLocation(loc *time.Location) Option { return func(b *Balancer) { b.location = loc } }
+
+// WithClock replaces time.Now to tell the current time
+func WithClock(now func() time.Time) Option { return func(b *Balancer) { b.now = now } }
+
+// WithOnSaveError has onError called when the usage can't be saved, which doesn't fail the lookup
+func WithOnSaveError(onError func(error)) Option {
+	return func(b *Balancer) { b.onSaveError = onError }
+}
+
+// Balancer sends each lookup to one of its members, as its strategy decides, skipping the members whose budget
+// is spent until it resets. A member failing other than by finding nothing hands the lookup to the next one.
+// It is safe for concurrent use.
+type Balancer struct {
+	members      []Member
+	strategy     Strategy
+	store        Store
+	location     *time.Location
+	now          func() time.Time
+	onSaveError  func(error)
+	saveInterval time.Duration
+
+	// saving is held while the usage is written to the store, one save at a time
+	saving sync.Mutex
+
+	mu    sync.Mutex
+	usage map[string]Usage
+	// dirty reports usage not saved yet, saved is when it last was
+	dirty bool
+	saved time.Time
+	// next is the member round robin asks first next time
+	next int
+	// weights are the current weights of smooth weighted round robin
+	weights []int
+}
+
+// Geocoder balances geocoders round robin, without budgets
+func Geocoder(geocoders ...geo.Geocoder) geo.Geocoder {
+	members := make([]Member, len(geocoders))
+	for i, g := range geocoders {
+		members[i] = Member{Geocoder: g}
+	}
+	b, _ := New(RoundRobin, members)
+	return b
+}
+
+// New returns a Balancer of members, resuming from the usage of the store if there is one
+func New(strategy Strategy, members []Member, opts ...Option) (*Balancer, error) {
+	b := &Balancer{
+		members:      make([]Member, len(members)),
+		strategy:     strategy,
+		location:     time.UTC,
+		now:          time.Now,
+		saveInterval: DefaultSaveInterval,
+		usage:        map[string]Usage{},
+		weights:      make([]int, len(members)),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	for i, m := range members {
+		m.Name = geo.MemberName(m.Geocoder, m.Name, fmt.Sprintf("member %d", i+1))
+		m.Weight = max(1, m.Weight)
+		b.members[i] = m
+	}
+
+	if b.store != nil {
+		usage, err := b.store.Load()
+		if err != nil {
+			return nil, fmt.Errorf("loading usage: %w", err)
+		}
+		maps.Copy(b.usage, usage)
+	}
+	return b, nil
+}
+
+// Usage returns the usage of each member by name
+func (b *Balancer) Usage() map[string]Usage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now().In(b.location)
+	usage := make(map[string]Usage, len(b.members))
+	for _, m := range b.members {
+		usage[m.Name] = b.current(m.Name, now)
+	}
+	return usage
+}
+
+// Geocode returns location for address, giving each member asked geo.DefaultTimeout to answer
+func (b *Balancer) Geocode(address string) (*geo.Location, error) {
+	loc, _, err := b.geocode(context.Background(), geo.DefaultTimeout, address)
+	return loc, err
+}
+
+// GeocodeContext returns location for address
+func (b *Balancer) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	loc, _, err := b.GeocodeWithProvider(ctx, address)
+	return loc, err
+}
+
+// GeocodeWithProvider returns location for address along with the name of the member that found it
+func (b *Balancer) GeocodeWithProvider(ctx context.Context, address string) (*geo.Location, string, error) {
+	return b.geocode(ctx, 0, address)
+}
+
+func (b *Balancer) geocode(ctx context.Context, timeout time.Duration, address string) (*geo.Location, string, error) {
+	return balance(ctx, b, timeout, func(ctx context.Context, g geo.Geocoder) (*geo.Location, error) {
+		return geo.AsContextGeocoder(g).GeocodeContext(ctx, address)
+	})
+}
+
+// ReverseGeocode returns address for location, giving each member asked geo.DefaultTimeout to answer
+func (b *Balancer) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
+	addr, _, err := b.reverseGeocode(context.Background(), geo.DefaultTimeout, lat, lng)
+	return addr, err
+}
+
+// ReverseGeocodeContext returns address for location
+func (b *Balancer) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	addr, _, err := b.ReverseGeocodeWithProvider(ctx, lat, lng)
+	return addr, err
+}
+
+// ReverseGeocodeWithProvider returns address for location along with the name of the member that found it
+func (b *Balancer) ReverseGeocodeWithProvider(ctx context.Context, lat, lng float64) (*geo.Address, string, error) {
+	return b.reverseGeocode(ctx, 0, lat, lng)
+}
+
+func (b *Balancer) reverseGeocode(ctx context.Context, timeout time.Duration, lat, lng float64) (*geo.Address, string, error) {
+	return balance(ctx, b, timeout, func(ctx context.Context, g geo.Geocoder) (*geo.Address, error) {
+		return geo.AsContextGeocoder(g).ReverseGeocodeContext(ctx, lat, lng)
+	})
+}
+
+// balance asks the members of b in the order of its strategy, skipping those without budget left, until one answers
+// or fails for a reason another member wouldn't change. Each member is given timeout to answer, unless it is 0.
+func balance[T any](ctx context.Context, b *Balancer, timeout time.Duration, lookup func(context.Context, geo.Geocoder) (*T, error)) (*T, string, error) {
+	var errs []error
+	for _, i := range b.order() {
+		if ctx.Err() != nil {
+			return nil, "", geo.ContextError(ctx)
+		}
+		if !b.take(i) {
+			continue
+		}
+		b.save()
+		m := b.members[i]
+		v, err := geo.WithinTimeout(ctx, timeout, func(ctx context.Context) (*T, error) { return lookup(ctx, m.Geocoder) })
+		if err == nil && v == nil {
+			err = geo.ErrNotFound
+		}
+		if err == nil {
+			return v, m.Name, nil
+		}
+		if ctx.Err() != nil {
+			return nil, "", geo.ContextError(ctx)
+		}
+		err = geo.NamedError(m.Name, err)
+		if errors.Is(err, geo.ErrNotFound) || errors.Is(err, geo.ErrInvalidRequest) {
+			return nil, "", err
+		}
+		if errors.Is(err, geo.ErrQuotaExceeded) {
+			b.exhausted(i, err)
+			b.save()
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, "", errors.Join(errs...)
+	}
+	return nil, "", b.spent()
+}
+
+// order returns the members with budget left, the one the strategy picks first and the others after it
+func (b *Balancer) order() []int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now().In(b.location)
+	var available []int
+	for i := range b.members {
+		if b.available(i, now) {
+			available = append(available, i)
+		}
+	}
+	if len(available) == 0 {
+		return nil
+	}
+
+	switch b.strategy {
+	case Weighted:
+		total, best := 0, available[0]
+		for _, i := range available {
+			b.weights[i] += b.members[i].Weight
+			total += b.members[i].Weight
+			if b.weights[i] > b.weights[best] {
+				best = i
+			}
+		}
+		b.weights[best] -= total
+		first := slices.Index(available, best)
+		return slices.Concat(available[first:], available[:first])
+	case LeastUsed:
+		slices.SortStableFunc(available, func(i, j int) int {
+			u, v := b.current(b.members[i].Name, now), b.current(b.members[j].Name, now)
+			return cmp.Or(cmp.Compare(share(u, b.members[i].Budget), share(v, b.members[j].Budget)), cmp.Compare(u.Daily, v.Daily))
+		})
+		return available
+	default:
+		first, _ := slices.BinarySearch(available, b.next%len(b.members))
+		b.next = available[first%len(available)] + 1
+		return slices.Concat(available[first:], available[:first])
+	}
+}
+
+// share returns the largest share of its budgets usage spent
+func share(u Usage, budget Budget) float64 {
+	s := 0.0
+	if budget.Daily > 0 {
+		s = float64(u.Daily) / float64(budget.Daily)
+	}
+	if budget.Monthly > 0 {
+		s = max(s, float64(u.Monthly)/float64(budget.Monthly))
+	}
+	return s
+}
+
+// take counts a request to member i, unless its budget was spent since the order was decided
+func (b *Balancer) take(i int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now().In(b.location)
+	if !b.available(i, now) {
+		return false
+	}
+	u := b.current(b.members[i].Name, now)
+	u.Daily++
+	u.Monthly++
+	b.usage[b.members[i].Name] = u
+	b.dirty = true
+	return true
+}
+
+// exhausted skips member i until the time its quota exceeded error says, or the next day
+func (b *Balancer) exhausted(i int, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now().In(b.location)
+	u := b.current(b.members[i].Name, now)
+	var pe *geo.ProviderError
+	if errors.As(err, &pe) && pe.RetryAfter() > 0 {
+		u.Until = now.Add(pe.RetryAfter())
+	} else {
+		u.Until = nextDay(now)
+	}
+	b.usage[b.members[i].Name] = u
+	b.dirty = true
+}
+
+// spent returns the error of a lookup no member had budget left for, saying when one will again
+func (b *Balancer) spent() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now().In(b.location)
+	var wait time.Duration
+	for i := range b.members {
+		if d := b.reset(i, now).Sub(now); i == 0 || d < wait {
+			wait = d
+		}
+	}
+	return &geo.ProviderError{Status: "budget spent", Err: geo.ErrQuotaExceeded, Response: &geo.ResponseMeta{RetryAfter: max(0, wait)}}
+}
+
+// available reports whether member i has budget left at now
+func (b *Balancer) available(i int, now time.Time) bool {
+	m := b.members[i]
+	u := b.current(m.Name, now)
+	return !now.Before(u.Until) &&
+		(m.Budget.Daily <= 0 || u.Daily < m.Budget.Daily) &&
+		(m.Budget.Monthly <= 0 || u.Monthly < m.Budget.Monthly)
+}
+
+// reset returns when member i has budget left again
+func (b *Balancer) reset(i int, now time.Time) time.Time {
+	m := b.members[i]
+	u := b.current(m.Name, now)
+	at := now
+	if now.Before(u.Until) {
+		at = u.Until
+	}
+	if m.Budget.Daily > 0 && u.Daily >= m.Budget.Daily {
+		at = later(at, nextDay(now))
+	}
+	if m.Budget.Monthly > 0 && u.Monthly >= m.Budget.Monthly {
+		at = later(at, nextMonth(now))
+	}
+	return at
+}
+
+// current returns the usage of name, its counters reset if a new day or month started since
+func (b *Balancer) current(name string, now time.Time) Usage {
+	u := b.usage[name]
+	if day := now.Format(time.DateOnly); u.Day != day {
+		u.Day, u.Daily = day, 0
+	}
+	if month := now.Format("2006-01"); u.Month != month {
+		u.Month, u.Monthly = month, 0
+	}
+	return u
+}
+
+// Flush saves the usage not saved yet to the store, if any
+func (b *Balancer) Flush() error {
+	if b.store == nil {
+		return nil
+	}
+	b.saving.Lock()
+	defer b.saving.Unlock()
+	return b.write(true)
+}
+
+// save saves the usage to the store, if any, unless it was saved within the save interval or another save is running.
+// Lookups don't wait on the store but for the one saving, and never while holding b.mu.
+func (b *Balancer) save() {
+	if b.store == nil || !b.saving.TryLock() {
+		return
+	}
+	defer b.saving.Unlock()
+	if err := b.write(false); err != nil && b.onSaveError != nil {
+		b.onSaveError(err)
+	}
+}
+
+// write saves the usage not saved yet, unless it was saved within the save interval and force is false.
+// b.saving must be held.
+func (b *Balancer) write(force bool) error {
+	b.mu.Lock()
+	now := b.now()
+	if !b.dirty || !force && now.Sub(b.saved) < b.saveInterval {
+		b.mu.Unlock()
+		return nil
+	}
+	usage := maps.Clone(b.usage)
+	b.dirty, b.saved = false, now
+	b.mu.Unlock()
+
+	err := b.store.Save(usage)
+	if err != nil {
+		b.mu.Lock()
+		b.dirty = true
+		b.mu.Unlock()
+	}
+	return err
+}
+
+func nextDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+}
+
+func nextMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+}
+
+func later(t, u time.Time) time.Time {
+	if u.After(t) {
+		return u
+	}
+	return t
+}
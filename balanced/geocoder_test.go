@@ -0,0 +1,198 @@
+package balanced_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+	"github.com/codingsince1985/geo-golang/balanced"
+	"github.com/codingsince1985/geo-golang/data"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	addressFixture  = geo.Address{FormattedAddress: "64 Elizabeth Street, Melbourne, Victoria 3000, Australia"}
+	locationFixture = geo.Location{Lat: -37.814107, Lng: 144.96328}
+	fixtures        = data.Geocoder(
+		data.AddressToLocation{addressFixture: locationFixture},
+		data.LocationToAddress{locationFixture: addressFixture},
+	)
+)
+
+// clock is a time that tests move forward
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newClock() *clock { return &clock{time.Date(2024, time.January, 31, 22, 0, 0, 0, time.UTC)} }
+
+// quotaGeocoder says its quota is exceeded
+type quotaGeocoder struct{ retryAfter time.Duration }
+
+func (g quotaGeocoder) Geocode(string) (*geo.Location, error) {
+	return nil, &geo.ProviderError{Status: "429 Too Many Requests", Err: geo.ErrQuotaExceeded, Response: &geo.ResponseMeta{RetryAfter: g.retryAfter}}
+}
+
+func (g quotaGeocoder) ReverseGeocode(float64, float64) (*geo.Address, error) {
+	return nil, geo.ErrQuotaExceeded
+}
+
+// deadlineGeocoder records the deadline of its lookups, timing out after a while unless it is next
+type deadlineGeocoder struct {
+	geo.Geocoder
+	deadline time.Time
+	next     bool
+}
+
+func (g *deadlineGeocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	g.deadline, _ = ctx.Deadline()
+	if !g.next {
+		time.Sleep(10 * time.Millisecond)
+		return nil, geo.ErrTimeout
+	}
+	return &locationFixture, nil
+}
+
+func (g *deadlineGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	return nil, geo.ErrNotFound
+}
+
+func winners(t *testing.T, b *balanced.Balancer, n int) string {
+	var names []string
+	for range n {
+		_, name, err := b.GeocodeWithProvider(t.Context(), addressFixture.FormattedAddress)
+		assert.NoError(t, err)
+		names = append(names, name)
+	}
+	return strings.Join(names, " ")
+}
+
+func TestRoundRobin(t *testing.T) {
+	b, err := balanced.New(balanced.RoundRobin, []balanced.Member{
+		{Geocoder: fixtures, Name: "a"},
+		{Geocoder: fixtures, Name: "b", Budget: balanced.Budget{Daily: 2}},
+		{Geocoder: fixtures, Name: "c"},
+	}, balanced.WithClock(newClock().now))
+	assert.NoError(t, err)
+	assert.Equal(t, "a b c a b c a c a c", winners(t, b, 10))
+	assert.Equal(t, 2, b.Usage()["b"].Daily)
+}
+
+func TestWeighted(t *testing.T) {
+	b, err := balanced.New(balanced.Weighted, []balanced.Member{
+		{Geocoder: fixtures, Name: "a", Weight: 3},
+		{Geocoder: fixtures, Name: "b"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a a b a a a b a", winners(t, b, 8))
+}
+
+func TestLeastUsed(t *testing.T) {
+	b, err := balanced.New(balanced.LeastUsed, []balanced.Member{
+		{Geocoder: fixtures, Name: "small", Budget: balanced.Budget{Daily: 2}},
+		{Geocoder: fixtures, Name: "large", Budget: balanced.Budget{Daily: 6}},
+	}, balanced.WithClock(newClock().now))
+	assert.NoError(t, err)
+	assert.Equal(t, "small large large large small large large large", winners(t, b, 8))
+
+	_, err = b.Geocode(addressFixture.FormattedAddress)
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+	var pe *geo.ProviderError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, 2*time.Hour, pe.RetryAfter())
+}
+
+func TestBudgetResets(t *testing.T) {
+	c := newClock()
+	b, err := balanced.New(balanced.RoundRobin, []balanced.Member{
+		{Geocoder: fixtures, Name: "daily", Budget: balanced.Budget{Daily: 1}},
+		{Geocoder: fixtures, Name: "monthly", Budget: balanced.Budget{Monthly: 2}},
+	}, balanced.WithClock(c.now))
+	assert.NoError(t, err)
+	assert.Equal(t, "daily monthly monthly", winners(t, b, 3))
+	_, err = b.Geocode(addressFixture.FormattedAddress)
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+
+	// a new day and month on the 1st of February
+	c.t = c.t.Add(2 * time.Hour)
+	assert.Equal(t, "daily monthly monthly", winners(t, b, 3))
+
+	c.t = c.t.Add(24 * time.Hour)
+	assert.Equal(t, "daily", winners(t, b, 1))
+	_, err = b.Geocode(addressFixture.FormattedAddress)
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+}
+
+func TestQuotaExceeded(t *testing.T) {
+	c := newClock()
+	b, err := balanced.New(balanced.RoundRobin, []balanced.Member{
+		{Geocoder: quotaGeocoder{time.Minute}, Name: "exceeded"},
+		{Geocoder: fixtures, Name: "fixtures"},
+	}, balanced.WithClock(c.now))
+	assert.NoError(t, err)
+
+	// the lookup is handed to the next member, and the exceeded one is skipped until it may be asked again
+	assert.Equal(t, "fixtures fixtures fixtures", winners(t, b, 3))
+	assert.Equal(t, 1, b.Usage()["exceeded"].Daily)
+
+	c.t = c.t.Add(time.Minute)
+	assert.Equal(t, "fixtures", winners(t, b, 1))
+	assert.Equal(t, 2, b.Usage()["exceeded"].Daily)
+}
+
+func TestNotFound(t *testing.T) {
+	b, err := balanced.New(balanced.RoundRobin, []balanced.Member{
+		{Geocoder: fixtures, Name: "a"},
+		{Geocoder: fixtures, Name: "b"},
+	})
+	assert.NoError(t, err)
+	loc, err := b.Geocode("NOWHERE,TX")
+	assert.Nil(t, loc)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
+	assert.Equal(t, 1, b.Usage()["a"].Daily)
+	assert.Equal(t, 0, b.Usage()["b"].Daily)
+}
+
+func TestStore(t *testing.T) {
+	store := balanced.FileStore(filepath.Join(t.TempDir(), "usage.json"))
+	members := []balanced.Member{{Geocoder: fixtures, Name: "a", Budget: balanced.Budget{Daily: 3}}}
+	c := newClock()
+
+	b, err := balanced.New(balanced.RoundRobin, members, balanced.WithStore(store), balanced.WithClock(c.now))
+	assert.NoError(t, err)
+	assert.Equal(t, "a a", winners(t, b, 2))
+
+	// the second request came within the save interval of the first, so it waits for a flush to be saved
+	usage, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, usage["a"].Daily)
+	assert.NoError(t, b.Flush())
+
+	// a restart resumes from the usage saved
+	b, err = balanced.New(balanced.RoundRobin, members, balanced.WithStore(store), balanced.WithClock(c.now))
+	assert.NoError(t, err)
+	assert.Equal(t, balanced.Usage{Day: "2024-01-31", Daily: 2, Month: "2024-01", Monthly: 2}, b.Usage()["a"])
+	assert.Equal(t, "a", winners(t, b, 1))
+	_, err = b.Geocode(addressFixture.FormattedAddress)
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+}
+
+func TestReverseGeocode(t *testing.T) {
+	addr, err := balanced.Geocoder(quotaGeocoder{}, fixtures).ReverseGeocode(locationFixture.Lat, locationFixture.Lng)
+	assert.NoError(t, err)
+	assert.Equal(t, addressFixture, *addr)
+}
+
+func TestTimeoutPerMember(t *testing.T) {
+	// a member running out of time leaves the next one the whole of its own
+	slow, next := &deadlineGeocoder{}, &deadlineGeocoder{next: true}
+	loc, err := balanced.Geocoder(slow, next).Geocode(addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, locationFixture, *loc)
+	assert.WithinDuration(t, time.Now().Add(geo.DefaultTimeout), next.deadline, time.Second)
+	assert.True(t, next.deadline.After(slow.deadline))
+}
@@ -0,0 +1,67 @@
+package balanced
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Usage counts the requests sent to a provider in the current day and month of its budget
+type Usage struct {
+	Day     string `json:"day"`
+	Daily   int    `json:"daily"`
+	Month   string `json:"month"`
+	Monthly int    `json:"monthly"`
+	// Until is when a provider that said its quota was exceeded is asked again
+	Until time.Time `json:"until,omitzero"`
+}
+
+// Store keeps the usage of providers across restarts
+type Store interface {
+	Load() (map[string]Usage, error)
+	Save(map[string]Usage) error
+}
+
+// FileStore keeps usage as JSON in the file at path
+type FileStore string
+
+// Load returns the usage saved in the file, none if there is no file yet
+func (f FileStore) Load() (map[string]Usage, error) {
+	b, err := os.ReadFile(string(f))
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]Usage{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	usage := map[string]Usage{}
+	return usage, json.Unmarshal(b, &usage)
+}
+
+// Save replaces the usage saved in the file, so that a crash never leaves it half written
+func (f FileStore) Save(usage map[string]Usage) error {
+	b, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(string(f)), filepath.Base(string(f))+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(b)
+	if err == nil {
+		// flushed to disk before it replaces the file, or a crash could leave it empty once renamed
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), string(f))
+}
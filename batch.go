@@ -0,0 +1,131 @@
+package geo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DefaultBatchConcurrency is how many single lookups run at once for providers without a batch endpoint
+const DefaultBatchConcurrency = 4
+
+// BatchResult is the outcome of geocoding one address of a batch: either Result or Err is set
+type BatchResult struct {
+	Result *Result
+	Err    error
+}
+
+// BatchGeocoder geocodes many addresses at once.
+// Results are in the order of addresses, with an error of their own for addresses that could not be geocoded.
+// The error returned is for failures of the batch as a whole, such as rejected credentials.
+type BatchGeocoder interface {
+	GeocodeBatch(ctx context.Context, addresses []string) ([]BatchResult, error)
+}
+
+// AsBatchGeocoder returns g itself if it is a BatchGeocoder.
+// Otherwise the returned geocoder looks up DefaultBatchConcurrency addresses at a time.
+func AsBatchGeocoder(g Geocoder) BatchGeocoder {
+	if bg, ok := g.(BatchGeocoder); ok {
+		return bg
+	}
+	return batchGeocoder{g}
+}
+
+type batchGeocoder struct{ Geocoder }
+
+func (g batchGeocoder) GeocodeBatch(ctx context.Context, addresses []string) ([]BatchResult, error) {
+	return geocodeEach(ctx, g.Geocoder, addresses), nil
+}
+
+// Request is an HTTP request built by an EndpointBuilder, for endpoints that take more than a URL
+type Request struct {
+	Method, URL string
+	// ContentType and Body are left empty for requests without a body
+	ContentType string
+	Body        []byte
+}
+
+// BatchEndpointBuilder is implemented by EndpointBuilders whose provider can geocode many addresses in one request.
+// addresses are passed unescaped and there are at most BatchSize of them, any number if it is 0 or less.
+type BatchEndpointBuilder interface {
+	BatchSize() int
+	BatchRequest(addresses []string) Request
+}
+
+// BatchResponseParser is implemented by ResponseParsers that can parse the response of a batch request.
+// BatchResults returns one result per address sent, n of them, in the order they were sent.
+type BatchResponseParser interface {
+	BatchResults(n int) ([]BatchResult, error)
+}
+
+// GeocodeBatch geocodes addresses with as few requests to the batch endpoint of the provider as it allows.
+// Providers without one are sent DefaultBatchConcurrency single lookups at a time.
+// Batch endpoints can't narrow down their results, so they fail with ErrUnsupported when a search filter is set.
+func (g HTTPGeocoder) GeocodeBatch(ctx context.Context, addresses []string) ([]BatchResult, error) {
+	b, ok := g.EndpointBuilder.(BatchEndpointBuilder)
+	if !ok {
+		return geocodeEach(ctx, g, addresses), nil
+	}
+	if !g.Options.Filter.IsZero() {
+		return nil, NamedError(g.Provider, UnsupportedError("search filters on batch geocoding"))
+	}
+
+	size := b.BatchSize()
+	if size <= 0 {
+		size = max(1, len(addresses))
+	}
+	results := make([]BatchResult, 0, len(addresses))
+	for start := 0; start < len(addresses); start += size {
+		chunk := addresses[start:min(start+size, len(addresses))]
+		responseParser := g.ResponseParserFactory()
+		if err := g.send(ctx, b.BatchRequest(chunk), responseParser); err != nil {
+			return nil, err
+		}
+
+		p, ok := responseParser.(BatchResponseParser)
+		if !ok {
+			return nil, NamedError(g.Provider, UnsupportedError("batch geocoding"))
+		}
+		batch, err := p.BatchResults(len(chunk))
+		if err == nil && len(batch) != len(chunk) {
+			err = fmt.Errorf("%w: %d results for %d addresses", ErrProviderUnavailable, len(batch), len(chunk))
+		}
+		if err != nil {
+			return nil, NamedError(g.Provider, err)
+		}
+		for i := range batch {
+			if batch[i].Err != nil {
+				batch[i].Err = NamedError(g.Provider, batch[i].Err)
+			}
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+// geocodeEach looks up addresses one by one, DefaultBatchConcurrency at a time.
+// The addresses not looked up yet when ctx is done fail with its error.
+func geocodeEach(ctx context.Context, g Geocoder, addresses []string) []BatchResult {
+	results := make([]BatchResult, len(addresses))
+	sem := make(chan struct{}, DefaultBatchConcurrency)
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		if ctx.Err() != nil {
+			results[i] = BatchResult{Err: ContextError(ctx)}
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i] = BatchResult{Err: ContextError(ctx)}
+			continue
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			result, err := GeocodeBest(ctx, g, address)
+			results[i] = BatchResult{Result: result, Err: err}
+		})
+	}
+	wg.Wait()
+	return results
+}
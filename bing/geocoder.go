@@ -2,8 +2,8 @@
 package bing
 
 import (
-	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/codingsince1985/geo-golang"
@@ -17,7 +17,10 @@ type (
 				Point struct {
 					Coordinates []float64
 				}
-				Address struct {
+				Bbox       []float64 // south, west, north, east
+				EntityType string
+				Confidence string
+				Address    struct {
 					FormattedAddress string
 					AddressLine      string
 					AdminDistrict    string
@@ -28,6 +31,7 @@ type (
 				}
 			}
 		}
+		StatusCode   int
 		ErrorDetails []string
 	}
 )
@@ -37,6 +41,7 @@ func Geocoder(key string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getURL(key, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "bing",
 	}
 }
 
@@ -47,15 +52,55 @@ func getURL(key string, baseURLs ...string) string {
 	return "http://dev.virtualearth.net/REST/v1/Locations*key=" + key
 }
 
-func (b baseURL) GeocodeURL(address string) string {
-	return strings.Replace(string(b), "*", "?q="+address+"&", 1)
+func (b baseURL) GeocodeURL(address string) string { return b.GeocodeAllURL(address, 1) }
+
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return strings.Replace(string(b), "*", fmt.Sprintf("?q=%s&maxResults=%d&", address, limit), 1)
+}
+
+func (b baseURL) LanguageParams(language string) url.Values {
+	return url.Values{"culture": {language}}
+}
+
+// FilterURL biases toward bounds through userMapView and toward a proximity through userLocation.
+// Bing cannot restrict a query to countries or bounds.
+func (b baseURL) FilterURL(rawURL string, f geo.Filter) (string, error) {
+	switch {
+	case len(f.Countries) > 0:
+		return "", geo.UnsupportedError("country restriction")
+	case f.Bounds != nil && f.RestrictToBounds:
+		return "", geo.UnsupportedError("bounds restriction")
+	}
+
+	params := url.Values{}
+	if bb := f.Bounds; bb != nil {
+		params.Set("userMapView", fmt.Sprintf("%f,%f,%f,%f", bb.South, bb.West, bb.North, bb.East))
+	}
+	if p := f.Proximity; p != nil {
+		params.Set("userLocation", fmt.Sprintf("%f,%f", p.Lat, p.Lng))
+	}
+	return geo.SetParams(rawURL, params), nil
 }
 
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 	return strings.Replace(string(b), "*", fmt.Sprintf("/%f,%f?", l.Lat, l.Lng), 1)
 }
 
+func (r *geocodeResponse) err() error {
+	if len(r.ErrorDetails) == 0 {
+		return nil
+	}
+	err := geo.ErrInvalidRequest
+	if r.StatusCode >= 400 {
+		err = geo.HTTPStatusError(r.StatusCode)
+	}
+	return geo.StatusError(strings.Join(r.ErrorDetails, " "), err)
+}
+
 func (r *geocodeResponse) Location() (*geo.Location, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
 	if len(r.ResourceSets) <= 0 || len(r.ResourceSets[0].Resources) <= 0 {
 		return nil, nil
 	}
@@ -66,9 +111,56 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 	}, nil
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+	if len(r.ResourceSets) <= 0 {
+		return nil, nil
+	}
+	var results []geo.Result
+	for _, res := range r.ResourceSets[0].Resources {
+		c := res.Point.Coordinates
+		if len(c) < 2 {
+			continue
+		}
+		result := geo.Result{
+			Location:         geo.Location{Lat: c[0], Lng: c[1]},
+			FormattedAddress: res.Address.FormattedAddress,
+			PlaceType:        res.EntityType,
+			Precision:        bingPrecision(res.EntityType),
+			Confidence:       bingConfidence[res.Confidence],
+		}
+		if b := res.Bbox; len(b) == 4 {
+			result.BoundingBox = &geo.BoundingBox{South: b[0], West: b[1], North: b[2], East: b[3]}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// bingConfidence scales the High, Medium and Low confidence levels onto 0..1
+var bingConfidence = map[string]float64{"High": 1, "Medium": 0.5, "Low": 0.25}
+
+func bingPrecision(entityType string) geo.Precision {
+	switch entityType {
+	case "Address":
+		return geo.PrecisionRooftop
+	case "RoadBlock", "RoadIntersection", "Road":
+		return geo.PrecisionStreet
+	case "PopulatedPlace", "Neighborhood", "Postcode1", "Postcode2", "Postcode3", "Postcode4":
+		return geo.PrecisionCity
+	case "AdminDivision1", "AdminDivision2":
+		return geo.PrecisionRegion
+	case "CountryRegion":
+		return geo.PrecisionCountry
+	}
+	return geo.PrecisionUnknown
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
-	if len(r.ErrorDetails) > 0 {
-		return nil, errors.New(strings.Join(r.ErrorDetails, " "))
+	if err := r.err(); err != nil {
+		return nil, err
 	}
 	if len(r.ResourceSets) <= 0 || len(r.ResourceSets[0].Resources) <= 0 {
 		return nil, nil
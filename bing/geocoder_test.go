@@ -43,7 +43,7 @@ func TestReverseGeocodeWithNoResult(t *testing.T) {
 	geocoder := bing.Geocoder(key, ts.URL+"/")
 	addr, err := geocoder.ReverseGeocode(-37.81375, 164.97176)
 
-	assert.NoError(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
 
@@ -0,0 +1,279 @@
+// Package breaker stops sending requests to a provider that keeps failing, until it has had time to recover
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+const (
+	// DefaultConsecutiveFailures is how many failures in a row open the circuit unless WithConsecutiveFailures says otherwise
+	DefaultConsecutiveFailures = 5
+	// DefaultCooldown is how long the circuit stays open before probing the provider again
+	DefaultCooldown = 30 * time.Second
+	// buckets is how many slices the error rate window is counted in
+	buckets = 10
+)
+
+// ErrOpen is wrapped by the errors of requests refused while the circuit is open.
+// It wraps geo.ErrProviderUnavailable, so that callers falling back on it need not know about breakers.
+var ErrOpen = fmt.Errorf("circuit open: %w", geo.ErrProviderUnavailable)
+
+// State is the state of a circuit
+type State int
+
+const (
+	// Closed lets every request through
+	Closed State = iota
+	// Open refuses every request until the cooldown is over
+	Open
+	// HalfOpen lets a few probe requests through, closing the circuit if they succeed and opening it again if not
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// Option configures a Breaker
+type Option func(*Breaker)
+
+// WithConsecutiveFailures opens the circuit after n failures in a row, 0 to only open on error rate
+func WithConsecutiveFailures(n int) Option { return func(b *Breaker) { b.consecutiveFailures = n } }
+
+// WithErrorRate also opens the circuit when at least rate of the requests of the last window failed,
+// provided there were minRequests of them
+func WithErrorRate(rate float64, minRequests int, window time.Duration) Option {
+	return func(b *Breaker) { b.errorRate, b.minRequests, b.window = rate, minRequests, window }
+}
+
+// WithCooldown sets how long the circuit stays open before probing the provider again
+func WithCooldown(d time.Duration) Option { return func(b *Breaker) { b.cooldown = d } }
+
+// WithProbes sets how many requests are let through at once while half-open, all of which must succeed to close the circuit
+func WithProbes(n int) Option { return func(b *Breaker) { b.probes = max(1, n) } }
+
+// WithFailureClassifier replaces ProviderFailure to decide which errors count as failures
+func WithFailureClassifier(failure func(error) bool) Option {
+	return func(b *Breaker) { b.failure = failure }
+}
+
+// WithOnStateChange has onChange called on every transition of the circuit, e.g. to alert
+func WithOnStateChange(onChange func(from, to State)) Option {
+	return func(b *Breaker) { b.onChange = onChange }
+}
+
+// ProviderFailure reports whether err says the provider is failing, rather than the request being wrong:
+// timeouts, unavailable providers and exhausted quotas. Requests given up by the caller don't count.
+func ProviderFailure(err error) bool {
+	return errors.Is(err, geo.ErrTimeout) || errors.Is(err, geo.ErrProviderUnavailable) || errors.Is(err, geo.ErrQuotaExceeded)
+}
+
+// Breaker tracks the outcome of requests to a provider and refuses them while the circuit is open.
+// It is safe for concurrent use.
+type Breaker struct {
+	consecutiveFailures int
+	errorRate           float64
+	minRequests         int
+	window              time.Duration
+	cooldown            time.Duration
+	probes              int
+	failure             func(error) bool
+	onChange            func(from, to State)
+
+	mu    sync.Mutex
+	state State
+	// generation changes with every transition, so that outcomes of requests sent before it are ignored
+	generation uint64
+	failures   int
+	counts     [buckets]bucket
+	openedAt   time.Time
+	inFlight   int
+	succeeded  int
+}
+
+// bucket counts the requests of a slice of the error rate window
+type bucket struct {
+	start           time.Time
+	total, failures int
+}
+
+// New returns a closed Breaker
+func New(opts ...Option) *Breaker {
+	b := &Breaker{
+		consecutiveFailures: DefaultConsecutiveFailures,
+		cooldown:            DefaultCooldown,
+		probes:              1,
+		failure:             ProviderFailure,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// State returns the state of the circuit, which turns from open to half-open once the cooldown is over
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == Open && time.Since(b.openedAt) >= b.cooldown {
+		return HalfOpen
+	}
+	return b.state
+}
+
+// Do calls f unless the circuit is open, and counts its outcome
+func Do[T any](b *Breaker, f func() (T, error)) (T, error) {
+	return DoContext(context.Background(), b, f)
+}
+
+// DoContext is Do for an f bound by ctx. Its outcome isn't counted once ctx is done,
+// as a request the caller gave up on says nothing about the provider.
+func DoContext[T any](ctx context.Context, b *Breaker, f func() (T, error)) (T, error) {
+	generation, err := b.allow()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	v, err := f()
+	b.record(generation, err, ctx.Err() != nil)
+	return v, err
+}
+
+// allow returns the generation a request is sent in, or the error refusing it
+func (b *Breaker) allow() (uint64, error) {
+	b.mu.Lock()
+	var transition func()
+	defer func() {
+		b.mu.Unlock()
+		if transition != nil {
+			transition()
+		}
+	}()
+
+	now := time.Now()
+	if b.state == Open {
+		if wait := b.cooldown - now.Sub(b.openedAt); wait > 0 {
+			return 0, refused(wait)
+		}
+		transition = b.setState(HalfOpen)
+	}
+	if b.state == HalfOpen {
+		if b.inFlight >= b.probes {
+			return 0, refused(0)
+		}
+		b.inFlight++
+	}
+	return b.generation, nil
+}
+
+// refused returns the error of a request refused for wait more
+func refused(wait time.Duration) error {
+	// there is no response to speak of, only how long until the circuit lets requests through again
+	return &geo.ProviderError{Status: "circuit open", Err: ErrOpen, Response: &geo.ResponseMeta{RetryAfter: wait}}
+}
+
+// record counts the outcome of a request sent in generation, unless the caller gave up on it
+func (b *Breaker) record(generation uint64, err error, givenUp bool) {
+	b.mu.Lock()
+	var transition func()
+	defer func() {
+		b.mu.Unlock()
+		if transition != nil {
+			transition()
+		}
+	}()
+
+	if generation != b.generation {
+		return
+	}
+	if givenUp || errors.Is(err, context.Canceled) {
+		if b.state == HalfOpen {
+			b.inFlight--
+		}
+		return
+	}
+	failed := err != nil && b.failure(err)
+
+	switch b.state {
+	case HalfOpen:
+		b.inFlight--
+		if failed {
+			transition = b.setState(Open)
+		} else if b.succeeded++; b.succeeded >= b.probes {
+			transition = b.setState(Closed)
+		}
+	case Closed:
+		b.count(time.Now(), failed)
+		if failed {
+			b.failures++
+		} else {
+			b.failures = 0
+		}
+		if b.consecutiveFailures > 0 && b.failures >= b.consecutiveFailures || b.errorRateExceeded(time.Now()) {
+			transition = b.setState(Open)
+		}
+	}
+}
+
+// setState moves the circuit to state, and returns the call to the state change callback to make once unlocked
+func (b *Breaker) setState(state State) func() {
+	from := b.state
+	b.state = state
+	b.generation++
+	b.failures, b.inFlight, b.succeeded = 0, 0, 0
+	b.counts = [buckets]bucket{}
+	if state == Open {
+		b.openedAt = time.Now()
+	}
+	if b.onChange == nil {
+		return nil
+	}
+	return func() { b.onChange(from, state) }
+}
+
+// count adds the outcome of a request to the bucket of now
+func (b *Breaker) count(now time.Time, failed bool) {
+	if b.window <= 0 {
+		return
+	}
+	// windows shorter than buckets nanoseconds get 1ns buckets rather than none
+	size := max(b.window/buckets, 1)
+	start := now.Truncate(size)
+	c := &b.counts[start.UnixNano()/int64(size)%buckets]
+	if !c.start.Equal(start) {
+		*c = bucket{start: start}
+	}
+	c.total++
+	if failed {
+		c.failures++
+	}
+}
+
+// errorRateExceeded reports whether the requests of the window ending now failed at or above the error rate
+func (b *Breaker) errorRateExceeded(now time.Time) bool {
+	if b.window <= 0 || b.errorRate <= 0 {
+		return false
+	}
+	total, failures := 0, 0
+	for _, c := range b.counts {
+		if now.Sub(c.start) < b.window {
+			total += c.total
+			failures += c.failures
+		}
+	}
+	return total > 0 && total >= b.minRequests && float64(failures) >= b.errorRate*float64(total)
+}
@@ -0,0 +1,110 @@
+package breaker
+
+import (
+	"context"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+type breakerGeocoder struct {
+	geocoder geo.ContextGeocoder
+	multi    geo.MultiGeocoder
+	breaker  *Breaker
+	provider string
+}
+
+// Geocoder fails the lookups of geocoder at once, with an error wrapping ErrOpen, while the circuit of b is open.
+// A nil b is a new Breaker with the default options. Geocoders can share a Breaker to share their circuit.
+// Geocoders without candidate lookups answer GeocodeAll with their single location.
+func Geocoder(geocoder geo.Geocoder, b *Breaker) geo.Geocoder {
+	if b == nil {
+		b = New()
+	}
+	g := breakerGeocoder{geocoder: geo.AsContextGeocoder(geocoder), breaker: b}
+	g.multi, _ = geocoder.(geo.MultiGeocoder)
+	g.provider = geo.ProviderName(geocoder)
+	return g
+}
+
+// do calls f with ctx, bounded by timeout unless it is 0, through the breaker.
+// Only a timeout of the breaker's own counts as a failure: ctx running out is the caller giving up.
+func do[T any](ctx context.Context, timeout time.Duration, g breakerGeocoder, f func(context.Context) (T, error)) (T, error) {
+	v, err := DoContext(ctx, g.breaker, func() (T, error) { return geo.WithinTimeout(ctx, timeout, f) })
+	if err != nil {
+		err = geo.NamedError(g.provider, err)
+	}
+	return v, err
+}
+
+// Unwrap returns the geocoder whose lookups go through the breaker
+func (g breakerGeocoder) Unwrap() geo.Geocoder { return g.geocoder }
+
+// Geocode returns location for address
+func (g breakerGeocoder) Geocode(address string) (*geo.Location, error) {
+	return g.geocode(context.Background(), geo.DefaultTimeout, address)
+}
+
+// GeocodeContext returns location for address unless the circuit is open
+func (g breakerGeocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	return g.geocode(ctx, 0, address)
+}
+
+func (g breakerGeocoder) geocode(ctx context.Context, timeout time.Duration, address string) (*geo.Location, error) {
+	return do(ctx, timeout, g, func(ctx context.Context) (*geo.Location, error) { return g.geocoder.GeocodeContext(ctx, address) })
+}
+
+// GeocodeAll returns up to limit candidates for address
+func (g breakerGeocoder) GeocodeAll(address string, limit int) ([]geo.Result, error) {
+	return g.geocodeAll(context.Background(), geo.DefaultTimeout, address, limit)
+}
+
+// GeocodeAllContext returns up to limit candidates for address unless the circuit is open
+func (g breakerGeocoder) GeocodeAllContext(ctx context.Context, address string, limit int) ([]geo.Result, error) {
+	return g.geocodeAll(ctx, 0, address, limit)
+}
+
+func (g breakerGeocoder) geocodeAll(ctx context.Context, timeout time.Duration, address string, limit int) ([]geo.Result, error) {
+	return do(ctx, timeout, g, func(ctx context.Context) ([]geo.Result, error) {
+		if g.multi != nil {
+			return g.multi.GeocodeAllContext(ctx, address, limit)
+		}
+		loc, err := g.geocoder.GeocodeContext(ctx, address)
+		if err != nil || loc == nil {
+			return nil, err
+		}
+		return []geo.Result{{Location: *loc}}, nil
+	})
+}
+
+// GeocodeAddress returns location for a structured address
+func (g breakerGeocoder) GeocodeAddress(address geo.Address) (*geo.Location, error) {
+	return g.geocodeAddress(context.Background(), geo.DefaultTimeout, address)
+}
+
+// GeocodeAddressContext returns location for a structured address unless the circuit is open
+func (g breakerGeocoder) GeocodeAddressContext(ctx context.Context, address geo.Address) (*geo.Location, error) {
+	return g.geocodeAddress(ctx, 0, address)
+}
+
+func (g breakerGeocoder) geocodeAddress(ctx context.Context, timeout time.Duration, address geo.Address) (*geo.Location, error) {
+	return do(ctx, timeout, g, func(ctx context.Context) (*geo.Location, error) {
+		return geo.AsStructuredGeocoder(g.geocoder).GeocodeAddressContext(ctx, address)
+	})
+}
+
+// ReverseGeocode returns address for location
+func (g breakerGeocoder) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
+	return g.reverseGeocode(context.Background(), geo.DefaultTimeout, lat, lng)
+}
+
+// ReverseGeocodeContext returns address for location unless the circuit is open
+func (g breakerGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	return g.reverseGeocode(ctx, 0, lat, lng)
+}
+
+func (g breakerGeocoder) reverseGeocode(ctx context.Context, timeout time.Duration, lat, lng float64) (*geo.Address, error) {
+	return do(ctx, timeout, g, func(ctx context.Context) (*geo.Address, error) {
+		return g.geocoder.ReverseGeocodeContext(ctx, lat, lng)
+	})
+}
@@ -0,0 +1,171 @@
+package breaker_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+	"github.com/codingsince1985/geo-golang/breaker"
+	"github.com/codingsince1985/geo-golang/data"
+	"github.com/codingsince1985/geo-golang/openstreetmap"
+	"github.com/stretchr/testify/assert"
+)
+
+var location = geo.Location{Lat: -37.814107, Lng: 144.96328}
+
+// flakyGeocoder fails with err while it is set
+type flakyGeocoder struct {
+	geo.Geocoder
+	err   atomic.Value
+	calls atomic.Int32
+}
+
+func newFlakyGeocoder() *flakyGeocoder {
+	return &flakyGeocoder{Geocoder: data.Geocoder(
+		data.AddressToLocation{geo.Address{FormattedAddress: "Melbourne"}: location},
+		data.LocationToAddress{},
+	)}
+}
+
+func (g *flakyGeocoder) fail(err error) { g.err.Store(&err) }
+
+func (g *flakyGeocoder) Geocode(address string) (*geo.Location, error) {
+	g.calls.Add(1)
+	if err, _ := g.err.Load().(*error); err != nil && *err != nil {
+		return nil, *err
+	}
+	return g.Geocoder.Geocode(address)
+}
+
+func TestConsecutiveFailures(t *testing.T) {
+	var transitions []string
+	b := breaker.New(
+		breaker.WithConsecutiveFailures(3),
+		breaker.WithCooldown(50*time.Millisecond),
+		breaker.WithOnStateChange(func(from, to breaker.State) { transitions = append(transitions, from.String()+"->"+to.String()) }))
+	flaky := newFlakyGeocoder()
+	geocoder := breaker.Geocoder(flaky, b)
+
+	flaky.fail(geo.ErrProviderUnavailable)
+	for range 3 {
+		_, err := geocoder.Geocode("Melbourne")
+		assert.ErrorIs(t, err, geo.ErrProviderUnavailable)
+	}
+	assert.Equal(t, breaker.Open, b.State())
+
+	// open: fails fast without calling the provider
+	_, err := geocoder.Geocode("Melbourne")
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	assert.ErrorIs(t, err, geo.ErrProviderUnavailable)
+	var pe *geo.ProviderError
+	assert.True(t, errors.As(err, &pe))
+	assert.Greater(t, pe.RetryAfter(), time.Duration(0))
+	assert.EqualValues(t, 3, flaky.calls.Load())
+
+	// half-open: a failed probe opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, breaker.HalfOpen, b.State())
+	_, err = geocoder.Geocode("Melbourne")
+	assert.NotErrorIs(t, err, breaker.ErrOpen)
+	assert.Equal(t, breaker.Open, b.State())
+
+	// a successful probe closes it
+	time.Sleep(60 * time.Millisecond)
+	flaky.fail(nil)
+	loc, err := geocoder.Geocode("Melbourne")
+	assert.NoError(t, err)
+	assert.Equal(t, location, *loc)
+	assert.Equal(t, breaker.Closed, b.State())
+
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}, transitions)
+}
+
+func TestRequestErrorsDontCount(t *testing.T) {
+	b := breaker.New(breaker.WithConsecutiveFailures(1))
+	flaky := newFlakyGeocoder()
+	geocoder := breaker.Geocoder(flaky, b)
+
+	for _, err := range []error{geo.ErrNotFound, geo.ErrInvalidRequest, context.Canceled} {
+		flaky.fail(err)
+		_, _ = geocoder.Geocode("Melbourne")
+	}
+	flaky.fail(nil)
+	_, err := geocoder.Geocode("Melbourne")
+	assert.NoError(t, err)
+	assert.Equal(t, breaker.Closed, b.State())
+}
+
+func TestCallerTimeoutsDontCount(t *testing.T) {
+	b := breaker.New(breaker.WithConsecutiveFailures(1))
+	flaky := newFlakyGeocoder()
+	geocoder := breaker.Geocoder(flaky, b)
+
+	flaky.fail(geo.ErrTimeout)
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	_, err := geocoder.(geo.ContextGeocoder).GeocodeContext(ctx, "Melbourne")
+	assert.Error(t, err)
+	assert.Equal(t, breaker.Closed, b.State())
+
+	// the timeout of Geocode is the breaker's own
+	_, err = geocoder.Geocode("Melbourne")
+	assert.ErrorIs(t, err, geo.ErrTimeout)
+	assert.Equal(t, breaker.Open, b.State())
+}
+
+func TestErrorRate(t *testing.T) {
+	b := breaker.New(breaker.WithConsecutiveFailures(0), breaker.WithErrorRate(0.5, 4, time.Minute))
+	flaky := newFlakyGeocoder()
+	geocoder := breaker.Geocoder(flaky, b)
+
+	for i := range 3 {
+		if i%2 == 0 {
+			flaky.fail(geo.ErrTimeout)
+		} else {
+			flaky.fail(nil)
+		}
+		_, _ = geocoder.Geocode("Melbourne")
+	}
+	// 2 failures out of 3 requests, fewer than the minimum
+	assert.Equal(t, breaker.Closed, b.State())
+
+	flaky.fail(nil)
+	_, _ = geocoder.Geocode("Melbourne")
+	assert.Equal(t, breaker.Open, b.State())
+}
+
+func TestGeocoderSharedBreaker(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		resp.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	b := breaker.New(breaker.WithConsecutiveFailures(1))
+	forward := breaker.Geocoder(openstreetmap.GeocoderWithURL(ts.URL+"/"), b)
+	reverse := breaker.Geocoder(openstreetmap.GeocoderWithURL(ts.URL+"/"), b)
+
+	_, err := forward.Geocode("Melbourne")
+	assert.ErrorIs(t, err, geo.ErrProviderUnavailable)
+	_, err = reverse.ReverseGeocode(location.Lat, location.Lng)
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	var pe *geo.ProviderError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, "openstreetmap", pe.Provider)
+	assert.EqualValues(t, 1, requests.Load())
+}
+
+func TestErrorRateShortWindow(t *testing.T) {
+	b := breaker.New(breaker.WithConsecutiveFailures(0), breaker.WithErrorRate(0.5, 1, time.Nanosecond))
+	flaky := newFlakyGeocoder()
+	geocoder := breaker.Geocoder(flaky, b)
+
+	flaky.fail(geo.ErrTimeout)
+	assert.NotPanics(t, func() { _, _ = geocoder.Geocode("Melbourne") })
+}
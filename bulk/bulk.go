@@ -0,0 +1,255 @@
+// Package bulk geocodes streams of addresses and locations through any geo.Geocoder, a few lookups at a time
+package bulk
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+const (
+	// DefaultWorkers is how many lookups run at once unless WithWorkers says otherwise
+	DefaultWorkers = 4
+	// DefaultDedupeSize is how many distinct queries are remembered to answer identical ones without a lookup
+	DefaultDedupeSize = 100000
+)
+
+// Query is one input of a run: an address to geocode, or a location to reverse geocode
+type Query struct {
+	Address string
+	// Location is reverse geocoded when set, Address is ignored then
+	Location *geo.Location
+}
+
+// Address returns the query geocoding address
+func Address(address string) Query { return Query{Address: address} }
+
+// Coordinates returns the query reverse geocoding lat, lng
+func Coordinates(lat, lng float64) Query { return Query{Location: &geo.Location{Lat: lat, Lng: lng}} }
+
+// Result is the outcome of one query: Result for an address, Address for a location, or Err
+type Result struct {
+	Query   Query
+	Result  *geo.Result
+	Address *geo.Address
+	Err     error
+	// Duplicate is set when the query was answered by the lookup of an identical earlier one,
+	// whose Result or Address is shared
+	Duplicate bool
+}
+
+// Progress counts the results yielded so far
+type Progress struct {
+	Done       int
+	Failed     int
+	Duplicates int
+	Elapsed    time.Duration
+}
+
+// Option configures a Runner
+type Option func(*Runner)
+
+// WithWorkers sets how many lookups run at once
+func WithWorkers(n int) Option {
+	return func(r *Runner) {
+		if n > 0 {
+			r.workers = n
+		}
+	}
+}
+
+// WithDedupeSize sets how many distinct queries are remembered, the oldest being forgotten first.
+// 0 disables deduplication.
+func WithDedupeSize(n int) Option { return func(r *Runner) { r.dedupeSize = n } }
+
+// WithProgress has report called after every result, from the goroutine ranging over the results
+func WithProgress(report func(Progress)) Option { return func(r *Runner) { r.progress = report } }
+
+// Runner looks up queries through a geocoder with a pool of workers
+type Runner struct {
+	geocoder   geo.Geocoder
+	workers    int
+	dedupeSize int
+	progress   func(Progress)
+}
+
+// New returns a Runner looking up queries through g
+func New(g geo.Geocoder, opts ...Option) *Runner {
+	r := &Runner{geocoder: g, workers: DefaultWorkers, dedupeSize: DefaultDedupeSize}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// task is a query in flight, done is closed once result is set
+type task struct {
+	query  Query
+	result Result
+	done   chan struct{}
+	// same is the task of an identical earlier query, whose result is shared
+	same *task
+}
+
+// key identifies identical queries
+type key struct {
+	address  string
+	location geo.Location
+	reverse  bool
+}
+
+// Run looks up queries and yields their results in the order of queries, with the index of each query.
+// Slices are turned into queries with slices.Values, channels with Chan.
+// Only a few queries are read ahead of the results yielded, so queries may be an endless stream.
+// Stopping the iteration, or ctx being done, cancels the lookups in flight and stops reading queries.
+// A goroutine reading queries lives until queries yields its next one though, which for a channel may be never:
+// pass the channel through Chan with a context cancelled once done with the results.
+func (r *Runner) Run(ctx context.Context, queries iter.Seq[Query]) iter.Seq2[int, Result] {
+	return func(yield func(int, Result) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		jobs := make(chan *task, r.workers)
+		ordered := make(chan *task, 4*r.workers)
+		go r.dispatch(ctx, queries, jobs, ordered)
+		for range r.workers {
+			go func() {
+				for t := range jobs {
+					t.result = r.lookup(ctx, t.query)
+					close(t.done)
+				}
+			}()
+		}
+
+		var p Progress
+		start := time.Now()
+		i := 0
+		for t := range ordered {
+			var result Result
+			if t.same != nil {
+				<-t.same.done
+				result = t.same.result
+				result.Query, result.Duplicate = t.query, true
+				p.Duplicates++
+			} else {
+				<-t.done
+				result = t.result
+			}
+			p.Done++
+			if result.Err != nil {
+				p.Failed++
+			}
+			if r.progress != nil {
+				p.Elapsed = time.Since(start)
+				r.progress(p)
+			}
+			if !yield(i, result) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// dispatch sends queries to workers, and every task to ordered once it is sure to be done eventually
+func (r *Runner) dispatch(ctx context.Context, queries iter.Seq[Query], jobs, ordered chan<- *task) {
+	defer close(ordered)
+	defer close(jobs)
+
+	seen := map[key]*task{}
+	var oldest []key
+	for q := range queries {
+		if ctx.Err() != nil {
+			return
+		}
+		t := &task{query: q}
+		k := key{address: q.Address}
+		if q.Location != nil {
+			k = key{location: *q.Location, reverse: true}
+		}
+
+		if same, ok := seen[k]; ok {
+			t.same = same
+		} else {
+			t.done = make(chan struct{})
+			select {
+			case jobs <- t:
+			case <-ctx.Done():
+				return
+			}
+			if r.dedupeSize > 0 {
+				if len(oldest) == r.dedupeSize {
+					delete(seen, oldest[0])
+					oldest = oldest[1:]
+				}
+				seen[k] = t
+				oldest = append(oldest, k)
+			}
+		}
+
+		select {
+		case ordered <- t:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Runner) lookup(ctx context.Context, q Query) Result {
+	result := Result{Query: q}
+	if ctx.Err() != nil {
+		result.Err = geo.ContextError(ctx)
+		return result
+	}
+
+	if l := q.Location; l != nil {
+		result.Address, result.Err = geo.AsContextGeocoder(r.geocoder).ReverseGeocodeContext(ctx, l.Lat, l.Lng)
+		if result.Err == nil && result.Address == nil {
+			result.Err = geo.ErrNotFound
+		}
+		return result
+	}
+
+	result.Result, result.Err = geo.GeocodeBest(ctx, r.geocoder, q.Address)
+	return result
+}
+
+// Addresses returns the queries geocoding addresses
+func Addresses(addresses iter.Seq[string]) iter.Seq[Query] {
+	return func(yield func(Query) bool) {
+		for a := range addresses {
+			if !yield(Address(a)) {
+				return
+			}
+		}
+	}
+}
+
+// Locations returns the queries reverse geocoding locations
+func Locations(locations iter.Seq[geo.Location]) iter.Seq[Query] {
+	return func(yield func(Query) bool) {
+		for l := range locations {
+			if !yield(Query{Location: &l}) {
+				return
+			}
+		}
+	}
+}
+
+// Chan returns the values received from ch until it is closed or ctx is done
+func Chan[T any](ctx context.Context, ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok || !yield(v) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package bulk_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+	"github.com/codingsince1985/geo-golang/bulk"
+	"github.com/codingsince1985/geo-golang/data"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	melbourne = geo.Location{Lat: -37.814107, Lng: 144.96328}
+	sydney    = geo.Location{Lat: -33.868820, Lng: 151.209296}
+	fixtures  = data.Geocoder(
+		data.AddressToLocation{
+			geo.Address{FormattedAddress: "Melbourne"}: melbourne,
+			geo.Address{FormattedAddress: "Sydney"}:    sydney,
+		},
+		data.LocationToAddress{
+			melbourne: geo.Address{FormattedAddress: "Melbourne VIC, Australia"},
+		},
+	)
+)
+
+// slowGeocoder counts its lookups and how many run at once, each taking a delay depending on the address
+type slowGeocoder struct {
+	geo.Geocoder
+	lookups, running, maxRunning atomic.Int32
+}
+
+func (g *slowGeocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	g.lookups.Add(1)
+	n := g.running.Add(1)
+	defer g.running.Add(-1)
+	for m := g.maxRunning.Load(); n > m && !g.maxRunning.CompareAndSwap(m, n); m = g.maxRunning.Load() {
+	}
+
+	// the first addresses take longest, so that they complete last
+	delay := time.Millisecond
+	if address == "Melbourne" {
+		delay = 20 * time.Millisecond
+	}
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return geo.AsContextGeocoder(g.Geocoder).GeocodeContext(ctx, address)
+}
+
+func (g *slowGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	return geo.AsContextGeocoder(g.Geocoder).ReverseGeocodeContext(ctx, lat, lng)
+}
+
+func TestRun(t *testing.T) {
+	g := &slowGeocoder{Geocoder: fixtures}
+	var progress []bulk.Progress
+	runner := bulk.New(g, bulk.WithWorkers(2), bulk.WithProgress(func(p bulk.Progress) { progress = append(progress, p) }))
+
+	addresses := []string{"Melbourne", "Sydney", "Nowhere", "Sydney", "Melbourne"}
+	var results []bulk.Result
+	for i, r := range runner.Run(context.Background(), bulk.Addresses(slices.Values(addresses))) {
+		assert.Equal(t, len(results), i)
+		results = append(results, r)
+	}
+
+	assert.Len(t, results, len(addresses))
+	for i, r := range results {
+		assert.Equal(t, addresses[i], r.Query.Address)
+	}
+	assert.Equal(t, melbourne, results[0].Result.Location)
+	assert.Equal(t, sydney, results[1].Result.Location)
+	assert.ErrorIs(t, results[2].Err, geo.ErrNotFound)
+	assert.True(t, results[3].Duplicate)
+	assert.Equal(t, sydney, results[3].Result.Location)
+	assert.True(t, results[4].Duplicate)
+	assert.Equal(t, melbourne, results[4].Result.Location)
+
+	assert.EqualValues(t, 3, g.lookups.Load())
+	assert.LessOrEqual(t, g.maxRunning.Load(), int32(2))
+	assert.Len(t, progress, len(addresses))
+	last := progress[len(progress)-1]
+	assert.Equal(t, 5, last.Done)
+	assert.Equal(t, 1, last.Failed)
+	assert.Equal(t, 2, last.Duplicates)
+}
+
+func TestRunWithoutDedupe(t *testing.T) {
+	g := &slowGeocoder{Geocoder: fixtures}
+	runner := bulk.New(g, bulk.WithDedupeSize(0))
+	for _, r := range runner.Run(context.Background(), bulk.Addresses(slices.Values([]string{"Sydney", "Sydney"}))) {
+		assert.False(t, r.Duplicate)
+	}
+	assert.EqualValues(t, 2, g.lookups.Load())
+}
+
+func TestRunLocations(t *testing.T) {
+	runner := bulk.New(fixtures)
+	var results []bulk.Result
+	for _, r := range runner.Run(context.Background(), bulk.Locations(slices.Values([]geo.Location{melbourne, {Lat: 1, Lng: 1}}))) {
+		results = append(results, r)
+	}
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, "Melbourne VIC, Australia", results[0].Address.FormattedAddress)
+	assert.ErrorIs(t, results[1].Err, geo.ErrNotFound)
+}
+
+func TestRunStream(t *testing.T) {
+	// an endless stream of distinct queries, most of which are never read
+	queries := make(chan bulk.Query)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := 0; ; i++ {
+			select {
+			case queries <- bulk.Address(fmt.Sprintf("Nowhere %d", i)):
+			case <-stop:
+				return
+			}
+		}
+	})
+	defer wg.Wait()
+	defer close(stop)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner := bulk.New(&slowGeocoder{Geocoder: fixtures}, bulk.WithWorkers(8))
+	n := 0
+	for i, r := range runner.Run(ctx, bulk.Chan(ctx, queries)) {
+		assert.Equal(t, fmt.Sprintf("Nowhere %d", i), r.Query.Address)
+		if n++; n == 100 {
+			break
+		}
+	}
+	assert.Equal(t, 100, n)
+}
+
+func TestChanCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// nothing is ever sent, so only ctx stops the iteration
+	for range bulk.Chan(ctx, make(chan bulk.Query)) {
+		t.Fatal("unexpected query")
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runner := bulk.New(&slowGeocoder{Geocoder: fixtures})
+
+	n := 0
+	for range runner.Run(ctx, bulk.Addresses(slices.Values(slices.Repeat([]string{"Melbourne", "Nowhere"}, 1000)))) {
+		if n++; n == 1 {
+			cancel()
+		}
+	}
+	assert.Less(t, n, 2000)
+}
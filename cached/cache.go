@@ -0,0 +1,39 @@
+package cached
+
+import (
+	"time"
+
+	"github.com/patrickmn/go-cache"
+)
+
+// Cache stores the answers of a cached geocoder. A ttl of 0 keeps the value for the default duration of the cache.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	Get(key string) (any, bool)
+	Set(key string, value any, ttl time.Duration)
+	Delete(key string)
+}
+
+// a *cache.Cache of go-cache is a Cache as is, its default expiration applying to values set with a ttl of 0
+var _ Cache = (*cache.Cache)(nil)
+
+// entry is a value of a cache, with when it expires, never if zero
+type entry struct {
+	value   any
+	expires time.Time
+}
+
+func newEntry(value any, ttl, defaultTTL time.Duration) entry {
+	e := entry{value: value}
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	if ttl > 0 {
+		e.expires = time.Now().Add(ttl)
+	}
+	return e
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
@@ -0,0 +1,363 @@
+package cached
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+// minCompaction is how many records the log of a File holds at least before it is compacted
+const minCompaction = 1000
+
+// File is a Cache kept in an append-only log of JSON lines, replayed when opened so that values survive restarts.
+// The log is compacted once it holds more than twice as many records as values.
+// Only the values a cached geocoder sets are written to the log, others are kept in memory.
+// It is safe for concurrent use, but not by several processes at once.
+type File struct {
+	path       string
+	defaultTTL time.Duration
+
+	mu      sync.Mutex
+	log     *os.File
+	entries map[string]entry
+	records int
+	// err is the first error writing the log, returned by Close
+	err error
+}
+
+// record is a line of the log of a File, and of its exports
+type record struct {
+	Key string `json:"key"`
+	// Kind is the type of Value, empty for a deleted key
+	Kind    string          `json:"kind,omitempty"`
+	Value   json.RawMessage `json:"value,omitempty"`
+	Expires time.Time       `json:"expires,omitzero"`
+	// Fresh is when a value kept stale while revalidated goes stale
+	Fresh time.Time `json:"fresh,omitzero"`
+}
+
+const (
+	kindLocation = "location"
+	kindAddress  = "address"
+	kindNotFound = "not found"
+)
+
+// OpenFile opens the File cache at path, creating it if need be, holding values for defaultTTL unless told otherwise,
+// 0 for ever
+func OpenFile(path string, defaultTTL time.Duration) (*File, error) {
+	c := &File{path: path, defaultTTL: defaultTTL, entries: map[string]entry{}}
+	log, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	err = c.replay(log)
+	cut := errors.Is(err, errCut)
+	if err != nil && !cut {
+		log.Close()
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	c.log = log
+	if cut || !terminated(log) {
+		// rewrite the log, as appending to a line cut short would garble the next record
+		if err = c.compact(); err != nil {
+			log.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// replay loads the records of r, the last line of which may have been cut short by a crash
+func (c *File) replay(r io.Reader) error {
+	now := time.Now()
+	return readRecords(r, func(rec record) error {
+		c.records++
+		if rec.Kind == "" {
+			delete(c.entries, rec.Key)
+			return nil
+		}
+		value, err := decode(rec)
+		if err != nil {
+			return err
+		}
+		if e := (entry{value: value, expires: rec.Expires}); !e.expired(now) {
+			c.entries[rec.Key] = e
+		} else {
+			delete(c.entries, rec.Key)
+		}
+		return nil
+	})
+}
+
+// Get returns the value of key, unless it expired
+func (c *File) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || e.expired(time.Now()) {
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set sets the value of key for ttl, writing it to the log
+func (c *File) Set(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, newEntry(value, ttl, c.defaultTTL))
+}
+
+func (c *File) set(key string, e entry) {
+	prev, had := c.entries[key]
+	c.entries[key] = e
+	rec, ok := encode(key, e)
+	if !ok {
+		// the value is only kept in memory, the log mustn't bring back the one it replaces when replayed
+		if _, logged := encode(key, prev); had && logged {
+			c.append(record{Key: key})
+		}
+		return
+	}
+	c.append(rec)
+}
+
+// Delete removes the value of key, writing its removal to the log
+func (c *File) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		return
+	}
+	delete(c.entries, key)
+	c.append(record{Key: key})
+}
+
+// append writes rec to the log, compacting it if it grew too large
+func (c *File) append(rec record) {
+	if c.err != nil || c.log == nil {
+		return
+	}
+	b, err := json.Marshal(rec)
+	if err == nil {
+		_, err = c.log.Write(append(b, '\n'))
+	}
+	if err != nil {
+		c.err = err
+		return
+	}
+	if c.records++; c.records > minCompaction && c.records > 2*len(c.entries) {
+		c.err = c.compact()
+	}
+}
+
+// Compact rewrites the log with only the values that haven't expired
+func (c *File) Compact() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.log == nil {
+		return os.ErrClosed
+	}
+	return c.compact()
+}
+
+func (c *File) compact() error {
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	records, err := c.export(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), c.path); err != nil {
+		return err
+	}
+
+	log, err := os.OpenFile(c.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	c.log.Close()
+	c.log, c.records = log, records
+	return nil
+}
+
+// Export writes the values that haven't expired to w as JSON lines, to Import them in another cache
+func (c *File) Export(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.export(w)
+	return err
+}
+
+func (c *File) export(w io.Writer) (int, error) {
+	now := time.Now()
+	enc := json.NewEncoder(w)
+	records := 0
+	for key, e := range c.entries {
+		if e.expired(now) {
+			delete(c.entries, key)
+			continue
+		}
+		rec, ok := encode(key, e)
+		if !ok {
+			continue
+		}
+		if err := enc.Encode(rec); err != nil {
+			return records, err
+		}
+		records++
+	}
+	return records, nil
+}
+
+// Import sets the values of the JSON lines of r, as written by Export, keeping their expiry
+func (c *File) Import(r io.Reader) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	err := readRecords(r, func(rec record) error {
+		if rec.Kind == "" {
+			return nil
+		}
+		value, err := decode(rec)
+		if err != nil {
+			return err
+		}
+		if e := (entry{value: value, expires: rec.Expires}); !e.expired(now) {
+			c.set(rec.Key, e)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return c.err
+}
+
+// Close closes the log, returning the first error writing it if there was one
+func (c *File) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.log == nil {
+		return os.ErrClosed
+	}
+	err := c.log.Close()
+	c.log = nil
+	return errors.Join(c.err, err)
+}
+
+// errCut reports a last line cut short, without a line feed, that isn't a record
+var errCut = errors.New("last line cut short")
+
+// readRecords calls f with each record of the JSON lines of r
+func readRecords(r io.Reader, f func(record) error) error {
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		b, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		last := err == io.EOF
+		if b = bytes.TrimSpace(b); len(b) > 0 {
+			var rec record
+			if err := json.Unmarshal(b, &rec); err != nil {
+				if last {
+					return errCut
+				}
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			if err := f(rec); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+// terminated reports whether f is empty or ends with a line feed
+func terminated(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return err == nil
+	}
+	b := make([]byte, 1)
+	_, err = f.ReadAt(b, info.Size()-1)
+	return err == nil && b[0] == '\n'
+}
+
+// encode returns the record of a value, unless it isn't one a cached geocoder sets
+func encode(key string, e entry) (record, bool) {
+	rec := record{Key: key, Expires: e.expires}
+	value := e.value
+	if f, ok := value.(fresh); ok {
+		value, rec.Fresh = f.value, f.until
+	}
+	switch value.(type) {
+	case *geo.Location, geo.Location:
+		rec.Kind = kindLocation
+	case *geo.Address, geo.Address:
+		rec.Kind = kindAddress
+	case notFound:
+		rec.Kind = kindNotFound
+		return rec, true
+	default:
+		return rec, false
+	}
+	b, err := json.Marshal(value)
+	if err != nil || string(b) == "null" {
+		return rec, false
+	}
+	rec.Value = b
+	return rec, true
+}
+
+// decode returns the value of a record, as a cached geocoder sets it
+func decode(rec record) (any, error) {
+	var value any
+	switch rec.Kind {
+	case kindLocation:
+		var loc geo.Location
+		if err := json.Unmarshal(rec.Value, &loc); err != nil {
+			return nil, err
+		}
+		value = &loc
+	case kindAddress:
+		var addr geo.Address
+		if err := json.Unmarshal(rec.Value, &addr); err != nil {
+			return nil, err
+		}
+		value = &addr
+	case kindNotFound:
+		return notFound{}, nil
+	default:
+		return nil, fmt.Errorf("unknown kind %q", rec.Kind)
+	}
+	if !rec.Fresh.IsZero() {
+		value = fresh{value: value, until: rec.Fresh}
+	}
+	return value, nil
+}
@@ -0,0 +1,91 @@
+package cached
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+// flights coalesces concurrent lookups of the same key into a single call to the wrapped geocoder
+type flights struct {
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// call is a lookup in flight, cancelled once every caller waiting for it gave up, unless it runs in the background
+type call struct {
+	done       chan struct{}
+	value      any
+	err        error
+	waiters    int
+	background bool
+	cancel     context.CancelFunc
+}
+
+// do returns the answer of f for key, sharing it with the concurrent callers of do for the same key.
+// f is given a context which is only cancelled once every caller waiting for it gave up, or at the deadline
+// of the caller that started the call, though not before geo.DefaultTimeout so that others can still wait for it.
+func (g *flights) do(ctx context.Context, key string, f func(context.Context) (any, error)) (any, error) {
+	g.mu.Lock()
+	c := g.start(ctx, key, f)
+	c.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.value, c.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		if c.waiters--; c.waiters == 0 && !c.background {
+			c.cancel()
+			// later callers start over rather than join a cancelled call
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, geo.ContextError(ctx)
+	}
+}
+
+// background calls f for key without waiting for it, unless a call for key is already in flight.
+// f is given a context that isn't cancelled with ctx, but times out after geo.DefaultTimeout.
+func (g *flights) background(ctx context.Context, key string, f func(context.Context) (any, error)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.calls[key]; ok {
+		return
+	}
+	g.start(context.WithoutCancel(ctx), key, f).background = true
+}
+
+// start returns the call in flight for key, starting it if there is none, until the later of the deadline of ctx
+// and geo.DefaultTimeout from now. g.mu must be held.
+func (g *flights) start(ctx context.Context, key string, f func(context.Context) (any, error)) *call {
+	if c, ok := g.calls[key]; ok {
+		return c
+	}
+	if g.calls == nil {
+		g.calls = map[string]*call{}
+	}
+	deadline := time.Now().Add(geo.DefaultTimeout)
+	if d, ok := ctx.Deadline(); ok && d.After(deadline) {
+		deadline = d
+	}
+	ctx, cancel := context.WithDeadline(context.WithoutCancel(ctx), deadline)
+	c := &call{done: make(chan struct{}), cancel: cancel}
+	g.calls[key] = c
+	go func() {
+		defer cancel()
+		c.value, c.err = f(ctx)
+		g.mu.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	return c
+}
@@ -1,49 +1,163 @@
 package cached
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"time"
 
 	"github.com/codingsince1985/geo-golang"
-	"github.com/patrickmn/go-cache"
 )
 
 type cachedGeocoder struct {
-	Geocoder geo.Geocoder
-	Cache    *cache.Cache
+	Geocoder    geo.Geocoder
+	Cache       Cache
+	forwardTTL  time.Duration
+	reverseTTL  time.Duration
+	addressKey  func(string) string
+	locationKey func(lat, lng float64) string
+	negativeTTL time.Duration
+	stale       time.Duration
+	flights     *flights
 }
 
-// Geocoder creates a chain of Geocoders to lookup address and fallback on
-func Geocoder(geocoder geo.Geocoder, cache *cache.Cache) geo.Geocoder {
-	return cachedGeocoder{Geocoder: geocoder, Cache: cache}
+// DefaultNegativeTTL is how long addresses and locations found to be nowhere are cached unless told otherwise
+const DefaultNegativeTTL = time.Minute
+
+// notFound is the value cached for a lookup that found nothing
+type notFound struct{}
+
+// fresh is the value cached for a lookup served stale once until is over, while it is looked up again
+type fresh struct {
+	value any
+	until time.Time
 }
 
-// Geocode returns location for address
-func (c cachedGeocoder) Geocode(address string) (*geo.Location, error) {
-	// Check if we've cached this response
-	if cachedLoc, found := c.Cache.Get(address); found {
-		return cachedLoc.(*geo.Location), nil
+// Option configures a cached geocoder
+type Option func(*cachedGeocoder)
+
+// WithForwardTTL sets how long locations found for addresses are cached, 0 for the default of the cache
+func WithForwardTTL(ttl time.Duration) Option { return func(c *cachedGeocoder) { c.forwardTTL = ttl } }
+
+// WithReverseTTL sets how long addresses found for locations are cached, 0 for the default of the cache
+func WithReverseTTL(ttl time.Duration) Option { return func(c *cachedGeocoder) { c.reverseTTL = ttl } }
+
+// WithNegativeTTL sets how long lookups finding nothing are cached, so that they fail at once with geo.ErrNotFound.
+// A ttl of 0 or less doesn't cache them.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(c *cachedGeocoder) { c.negativeTTL = ttl }
+}
+
+// WithStaleWhileRevalidate keeps answers for window after their ttl is over, serving them while they are looked up
+// again in the background. Only lookups given a ttl by WithForwardTTL or WithReverseTTL are revalidated: the expiry
+// of the default ttl of 0 is up to the cache, so without either option WithStaleWhileRevalidate has no effect.
+func WithStaleWhileRevalidate(window time.Duration) Option {
+	return func(c *cachedGeocoder) { c.stale = window }
+}
+
+// WithAddressKey sets the key forward lookups are cached on, e.g. NormalizeAddress, the address as is by default.
+// Keys it sets are prefixed with "forward:", so that they never are those of reverse lookups.
+func WithAddressKey(key func(address string) string) Option {
+	return func(c *cachedGeocoder) {
+		c.addressKey = func(address string) string { return "forward:" + key(address) }
+	}
+}
+
+// WithLocationKey sets the key reverse lookups are cached on, e.g. Geohash or Grid to share entries between
+// nearby locations, ExactLocation by default. Keys it sets are prefixed with "reverse:", so that they never are
+// those of forward lookups.
+func WithLocationKey(key func(lat, lng float64) string) Option {
+	return func(c *cachedGeocoder) {
+		c.locationKey = func(lat, lng float64) string { return "reverse:" + key(lat, lng) }
 	}
+}
 
-	if loc, err := c.Geocoder.Geocode(address); err != nil {
-		return loc, err
-	} else {
-		c.Cache.Set(address, loc, 0)
-		return loc, nil
+// Geocoder caches the answers of geocoder in cache, e.g. a *cache.Cache of go-cache, an LRU or a Sharded cache.
+// Concurrent lookups of the same key share a single call to geocoder.
+func Geocoder(geocoder geo.Geocoder, cache Cache, opts ...Option) geo.Geocoder {
+	c := cachedGeocoder{
+		Geocoder:    geocoder,
+		Cache:       cache,
+		addressKey:  rawAddress,
+		locationKey: ExactLocation,
+		negativeTTL: DefaultNegativeTTL,
+		flights:     &flights{},
+	}
+	for _, opt := range opts {
+		opt(&c)
 	}
+	return c
+}
+
+// Unwrap returns the geocoder whose lookups are cached
+func (c cachedGeocoder) Unwrap() geo.Geocoder { return c.Geocoder }
+
+// Geocode returns location for address
+func (c cachedGeocoder) Geocode(address string) (*geo.Location, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), geo.DefaultTimeout)
+	defer cancel()
+
+	return c.GeocodeContext(ctx, address)
+}
+
+// GeocodeContext returns location for address, passing ctx on to the wrapped geocoder on a cache miss
+func (c cachedGeocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	return lookup(ctx, c, c.addressKey(address), c.forwardTTL, func(ctx context.Context) (*geo.Location, error) {
+		return geo.AsContextGeocoder(c.Geocoder).GeocodeContext(ctx, address)
+	})
 }
 
 // ReverseGeocode returns address for location
 func (c cachedGeocoder) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
-	// Check if we've cached this response
-	locKey := fmt.Sprintf("geo.Location{%f,%f}", lat, lng)
-	if cachedAddr, found := c.Cache.Get(locKey); found {
-		return cachedAddr.(*geo.Address), nil
+	ctx, cancel := context.WithTimeout(context.Background(), geo.DefaultTimeout)
+	defer cancel()
+
+	return c.ReverseGeocodeContext(ctx, lat, lng)
+}
+
+// ReverseGeocodeContext returns address for location, passing ctx on to the wrapped geocoder on a cache miss
+func (c cachedGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	return lookup(ctx, c, c.locationKey(lat, lng), c.reverseTTL, func(ctx context.Context) (*geo.Address, error) {
+		return geo.AsContextGeocoder(c.Geocoder).ReverseGeocodeContext(ctx, lat, lng)
+	})
+}
+
+// lookup returns the answer cached for key, or that of f, cached for ttl. Lookups are coalesced by key.
+func lookup[T any](ctx context.Context, c cachedGeocoder, key string, ttl time.Duration, f func(context.Context) (*T, error)) (*T, error) {
+	fetch := func(ctx context.Context) (any, error) {
+		v, err := f(ctx)
+		switch {
+		case err == nil && v != nil:
+			if c.stale > 0 && ttl > 0 {
+				c.Cache.Set(key, fresh{value: v, until: time.Now().Add(ttl)}, ttl+c.stale)
+			} else {
+				c.Cache.Set(key, v, ttl)
+			}
+		case (err == nil || errors.Is(err, geo.ErrNotFound)) && c.negativeTTL > 0:
+			c.Cache.Set(key, notFound{}, c.negativeTTL)
+		}
+		return v, err
 	}
 
-	if addr, err := c.Geocoder.ReverseGeocode(lat, lng); err != nil {
-		return nil, err
-	} else {
-		c.Cache.Set(locKey, addr, 0)
-		return addr, nil
+	// Check if we've cached this response
+	if cached, found := c.Cache.Get(key); found {
+		switch cached := cached.(type) {
+		case *T:
+			return cached, nil
+		case notFound:
+			return nil, geo.ErrNotFound
+		case fresh:
+			if v, ok := cached.value.(*T); ok {
+				if time.Now().After(cached.until) {
+					c.flights.background(ctx, key, fetch)
+				}
+				return v, nil
+			}
+		}
 	}
+
+	v, err := c.flights.do(ctx, key, fetch)
+	t, _ := v.(*T)
+	return t, err
 }
+
+func rawAddress(address string) string { return address }
@@ -1,7 +1,14 @@
 package cached_test
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -52,7 +59,7 @@ func TestReverseGeocode(t *testing.T) {
 
 func TestReverseGeocodeWithNoResult(t *testing.T) {
 	addr, err := geocoder.ReverseGeocode(1, 2)
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
 
@@ -80,6 +87,424 @@ func TestCachedGeocode(t *testing.T) {
 	assert.Equal(t, geo.Location{Lat: 1, Lng: 2}, *l)
 
 	addr, err := c.Geocode("NOWHERE,TX")
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
+
+func TestCacheTTL(t *testing.T) {
+	for name, c := range map[string]cached.Cache{
+		"go-cache": cache.New(cache.NoExpiration, 0),
+		"lru":      cached.NewLRU(10, 0),
+		"sharded":  cached.NewSharded(0, 0),
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := cached.Geocoder(data.Geocoder(
+				data.AddressToLocation{addressFixture: locationFixture},
+				data.LocationToAddress{locationFixture: addressFixture},
+			), c, cached.WithForwardTTL(20*time.Millisecond), cached.WithReverseTTL(time.Hour))
+
+			_, err := g.Geocode(addressFixture.FormattedAddress)
+			assert.NoError(t, err)
+			_, err = g.ReverseGeocode(locationFixture.Lat, locationFixture.Lng)
+			assert.NoError(t, err)
+
+			loc, found := c.Get(addressFixture.FormattedAddress)
+			assert.True(t, found)
+			assert.Equal(t, &locationFixture, loc)
+
+			time.Sleep(30 * time.Millisecond)
+			_, found = c.Get(addressFixture.FormattedAddress)
+			assert.False(t, found)
+			_, found = c.Get("geo.Location{-37.814107,144.963280}")
+			assert.True(t, found)
+
+			c.Delete("geo.Location{-37.814107,144.963280}")
+			_, found = c.Get("geo.Location{-37.814107,144.963280}")
+			assert.False(t, found)
+		})
+	}
+}
+
+func TestLRU(t *testing.T) {
+	c := cached.NewLRU(2, 0)
+	c.Set("a", 1, 0)
+	c.Set("b", 2, 0)
+	_, found := c.Get("a")
+	assert.True(t, found)
+
+	// b is the value used least recently
+	c.Set("c", 3, 0)
+	assert.Equal(t, 2, c.Len())
+	_, found = c.Get("b")
+	assert.False(t, found)
+	v, found := c.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, 1, v)
+
+	c.Set("a", 4, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	_, found = c.Get("a")
+	assert.False(t, found)
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestSharded(t *testing.T) {
+	c := cached.NewSharded(4, time.Nanosecond)
+	c.Set("expiring", 1, 0)
+	c.Set("lasting", 2, time.Hour)
+	time.Sleep(time.Millisecond)
+	c.DeleteExpired()
+
+	_, found := c.Get("expiring")
+	assert.False(t, found)
+	v, found := c.Get("lasting")
+	assert.True(t, found)
+	assert.Equal(t, 2, v)
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+	c, err := cached.OpenFile(path, 0)
+	assert.NoError(t, err)
+
+	g := cached.Geocoder(data.Geocoder(
+		data.AddressToLocation{addressFixture: locationFixture},
+		data.LocationToAddress{locationFixture: addressFixture},
+	), c)
+	_, err = g.Geocode(addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	_, err = g.ReverseGeocode(locationFixture.Lat, locationFixture.Lng)
+	assert.NoError(t, err)
+	c.Set("expiring", &geo.Location{Lat: 1, Lng: 2}, time.Millisecond)
+	c.Set("deleted", &geo.Location{Lat: 1, Lng: 2}, 0)
+	c.Delete("deleted")
+	assert.NoError(t, c.Close())
+
+	// a restart finds the values again, answering without the geocoder
+	time.Sleep(2 * time.Millisecond)
+	c, err = cached.OpenFile(path, 0)
+	assert.NoError(t, err)
+	defer c.Close()
+	g = cached.Geocoder(data.Geocoder(data.AddressToLocation{}, data.LocationToAddress{}), c)
+	loc, err := g.Geocode(addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, locationFixture, *loc)
+	addr, err := g.ReverseGeocode(locationFixture.Lat, locationFixture.Lng)
+	assert.NoError(t, err)
+	assert.Equal(t, addressFixture, *addr)
+	_, found := c.Get("expiring")
+	assert.False(t, found)
+	_, found = c.Get("deleted")
+	assert.False(t, found)
+
+	// compaction keeps only the values left
+	assert.NoError(t, c.Compact())
+	assert.Equal(t, 2, lines(t, path))
+}
+
+func TestFileCompactsItself(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+	c, err := cached.OpenFile(path, 0)
+	assert.NoError(t, err)
+	defer c.Close()
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Go(func() {
+			for j := range 1000 {
+				c.Set(fmt.Sprint(j%10), &geo.Location{Lat: float64(i), Lng: float64(j)}, 0)
+			}
+		})
+	}
+	wg.Wait()
+	assert.Less(t, lines(t, path), 1100)
+
+	v, found := c.Get("9")
+	assert.True(t, found)
+	assert.Equal(t, 999.0, v.(*geo.Location).Lng)
+}
+
+func TestFileValueInMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+	c, err := cached.OpenFile(path, 0)
+	assert.NoError(t, err)
+	c.Set("a", &geo.Location{Lat: 1, Lng: 2}, 0)
+	// a value kept in memory replaces the one in the log
+	c.Set("a", 42, 0)
+	assert.NoError(t, c.Close())
+
+	c, err = cached.OpenFile(path, 0)
+	assert.NoError(t, err)
+	defer c.Close()
+	_, found := c.Get("a")
+	assert.False(t, found)
+}
+
+func TestFileCutShort(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+	record := `{"key":"a","kind":"location","value":{"Lat":1,"Lng":2}}` + "\n"
+	assert.NoError(t, os.WriteFile(path, []byte(record+`{"key":"b","kind":"loc`), 0o644))
+
+	c, err := cached.OpenFile(path, 0)
+	assert.NoError(t, err)
+	c.Set("c", &geo.Location{Lat: 3, Lng: 4}, 0)
+	assert.NoError(t, c.Close())
+
+	c, err = cached.OpenFile(path, 0)
+	assert.NoError(t, err)
+	defer c.Close()
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		_, found := c.Get(key)
+		assert.Equal(t, want, found, key)
+	}
+}
+
+func TestFileExportImport(t *testing.T) {
+	from, err := cached.OpenFile(filepath.Join(t.TempDir(), "from.jsonl"), 0)
+	assert.NoError(t, err)
+	defer from.Close()
+	from.Set("location", &locationFixture, time.Hour)
+	from.Set("address", &addressFixture, 0)
+	from.Set("in memory only", 42, 0)
+
+	var b bytes.Buffer
+	assert.NoError(t, from.Export(&b))
+	assert.Equal(t, 2, strings.Count(b.String(), "\n"))
+
+	to, err := cached.OpenFile(filepath.Join(t.TempDir(), "to.jsonl"), 0)
+	assert.NoError(t, err)
+	defer to.Close()
+	assert.NoError(t, to.Import(&b))
+	loc, found := to.Get("location")
+	assert.True(t, found)
+	assert.Equal(t, &locationFixture, loc)
+	addr, found := to.Get("address")
+	assert.True(t, found)
+	assert.Equal(t, &addressFixture, addr)
+	_, found = to.Get("in memory only")
+	assert.False(t, found)
+
+	assert.Error(t, to.Import(strings.NewReader(`{"key":"x","kind":"unknown","value":{}}`+"\n")))
+}
+
+func lines(t *testing.T, path string) int {
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	return bytes.Count(b, []byte("\n"))
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	for _, address := range []string{"64 Elizabeth St.", " 64  elizabeth st ", "64, ELIZABETH ST"} {
+		assert.Equal(t, "64 elizabeth st", cached.NormalizeAddress(address))
+	}
+	assert.Equal(t, "cafe de flore paris", cached.NormalizeAddress("Café de Flore, Paris"))
+	assert.Equal(t, "cafe", cached.NormalizeAddress("Cafe\u0301"))
+	assert.Equal(t, "1 rue", cached.NormalizeAddress("１ Ｒｕｅ"))
+}
+
+func TestLocationKeys(t *testing.T) {
+	assert.Equal(t, "geohash:ezs42", cached.Geohash(5)(42.605, -5.603))
+	assert.Equal(t, "geohash:r1r0fsp", cached.Geohash(7)(locationFixture.Lat, locationFixture.Lng))
+
+	grid := cached.Grid(50)
+	assert.Equal(t, grid(-37.81411, 144.96328), grid(-37.81412, 144.96329))
+	assert.NotEqual(t, grid(-37.81411, 144.96328), grid(-37.81511, 144.96328))
+	assert.NotEqual(t, grid(-37.81411, 144.96328), grid(-37.81411, 144.96428))
+	assert.Panics(t, func() { cached.Grid(0) })
+	assert.Panics(t, func() { cached.Grid(-50) })
+}
+
+func TestCacheKeys(t *testing.T) {
+	c := cached.NewLRU(10, 0)
+	g := cached.Geocoder(data.Geocoder(
+		data.AddressToLocation{addressFixture: locationFixture},
+		data.LocationToAddress{locationFixture: addressFixture},
+	), c, cached.WithAddressKey(cached.NormalizeAddress), cached.WithLocationKey(cached.Geohash(7)))
+
+	_, err := g.Geocode(addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	loc, err := g.Geocode("64 ELIZABETH STREET melbourne victoria 3000 australia")
+	assert.NoError(t, err)
+	assert.Equal(t, locationFixture, *loc)
+
+	_, err = g.ReverseGeocode(locationFixture.Lat, locationFixture.Lng)
+	assert.NoError(t, err)
+	addr, err := g.ReverseGeocode(locationFixture.Lat+0.00001, locationFixture.Lng-0.00001)
+	assert.NoError(t, err)
+	assert.Equal(t, addressFixture, *addr)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestCacheKeysOfBothKinds(t *testing.T) {
+	// forward and reverse lookups keyed the same don't share an entry
+	same := func(string) string { return "same" }
+	c := cached.NewLRU(10, 0)
+	g := cached.Geocoder(data.Geocoder(
+		data.AddressToLocation{addressFixture: locationFixture},
+		data.LocationToAddress{locationFixture: addressFixture},
+	), c, cached.WithAddressKey(same), cached.WithLocationKey(func(lat, lng float64) string { return "same" }))
+
+	loc, err := g.Geocode(addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, locationFixture, *loc)
+	addr, err := g.ReverseGeocode(locationFixture.Lat, locationFixture.Lng)
+	assert.NoError(t, err)
+	assert.Equal(t, addressFixture, *addr)
+	assert.Equal(t, 2, c.Len())
+}
+
+// countingGeocoder finds every address at a longitude counting its calls, once release is closed if there is one
+type countingGeocoder struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (g *countingGeocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	n := g.calls.Add(1)
+	if g.release != nil {
+		select {
+		case <-g.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if address == "NOWHERE,TX" {
+		return nil, geo.ErrNotFound
+	}
+	return &geo.Location{Lat: 1, Lng: float64(n)}, nil
+}
+
+func (g *countingGeocoder) Geocode(address string) (*geo.Location, error) {
+	return g.GeocodeContext(context.Background(), address)
+}
+
+func (g *countingGeocoder) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
+	return nil, geo.ErrNotFound
+}
+
+func TestNegativeCache(t *testing.T) {
+	g := &countingGeocoder{}
+	c := cached.Geocoder(g, cached.NewLRU(10, 0), cached.WithNegativeTTL(20*time.Millisecond))
+	for range 2 {
+		loc, err := c.Geocode("NOWHERE,TX")
+		assert.Nil(t, loc)
+		assert.ErrorIs(t, err, geo.ErrNotFound)
+	}
+	assert.EqualValues(t, 1, g.calls.Load())
+
+	time.Sleep(30 * time.Millisecond)
+	_, err := c.Geocode("NOWHERE,TX")
+	assert.ErrorIs(t, err, geo.ErrNotFound)
+	assert.EqualValues(t, 2, g.calls.Load())
+
+	g = &countingGeocoder{}
+	c = cached.Geocoder(g, cached.NewLRU(10, 0), cached.WithNegativeTTL(0))
+	for range 2 {
+		_, err = c.Geocode("NOWHERE,TX")
+		assert.ErrorIs(t, err, geo.ErrNotFound)
+	}
+	assert.EqualValues(t, 2, g.calls.Load())
+}
+
+func TestCoalescing(t *testing.T) {
+	g := &countingGeocoder{release: make(chan struct{})}
+	c := cached.Geocoder(g, cached.NewLRU(10, 0))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			loc, err := c.Geocode("42, Some Street, Austin, Texas")
+			assert.NoError(t, err)
+			assert.Equal(t, geo.Location{Lat: 1, Lng: 1}, *loc)
+		})
+	}
+
+	// a caller giving up doesn't cancel the lookup the others wait for
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := c.(geo.ContextGeocoder).GeocodeContext(ctx, "42, Some Street, Austin, Texas")
+	assert.ErrorIs(t, err, geo.ErrTimeout)
+
+	time.Sleep(10 * time.Millisecond)
+	close(g.release)
+	wg.Wait()
+	assert.EqualValues(t, 1, g.calls.Load())
+}
+
+// deadlineGeocoder finds every address, recording the deadline of the lookup
+type deadlineGeocoder struct {
+	deadline time.Time
+	ok       bool
+}
+
+func (g *deadlineGeocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	g.deadline, g.ok = ctx.Deadline()
+	return &geo.Location{Lat: 1, Lng: 2}, nil
+}
+
+func (g *deadlineGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	return nil, geo.ErrNotFound
+}
+
+func (g *deadlineGeocoder) Geocode(address string) (*geo.Location, error) {
+	return g.GeocodeContext(context.Background(), address)
+}
+
+func (g *deadlineGeocoder) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
+	return nil, geo.ErrNotFound
+}
+
+func TestCoalescingDeadline(t *testing.T) {
+	// the lookup shared by callers without a deadline still has one
+	g := &deadlineGeocoder{}
+	c := cached.Geocoder(g, cached.NewLRU(10, 0)).(geo.ContextGeocoder)
+	_, err := c.GeocodeContext(context.Background(), "Melbourne")
+	assert.NoError(t, err)
+	assert.True(t, g.ok)
+	assert.WithinDuration(t, time.Now().Add(geo.DefaultTimeout), g.deadline, time.Second)
+
+	// nor is it cut short by a caller with a later one
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	_, err = c.GeocodeContext(ctx, "Sydney")
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), g.deadline, time.Second)
+}
+
+func TestStaleWhileRevalidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.jsonl")
+	file, err := cached.OpenFile(path, 0)
+	assert.NoError(t, err)
+	g := &countingGeocoder{}
+	c := cached.Geocoder(g, file, cached.WithForwardTTL(20*time.Millisecond), cached.WithStaleWhileRevalidate(time.Hour))
+
+	loc, err := c.Geocode("42, Some Street, Austin, Texas")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, loc.Lng)
+
+	// the stale location is served while the next one is looked up
+	time.Sleep(30 * time.Millisecond)
+	loc, err = c.Geocode("42, Some Street, Austin, Texas")
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, loc.Lng)
+	assert.Eventually(t, func() bool {
+		loc, err := c.Geocode("42, Some Street, Austin, Texas")
+		return err == nil && loc.Lng == 2
+	}, time.Second, time.Millisecond)
+	assert.EqualValues(t, 2, g.calls.Load())
+
+	// and it survives a restart, as do negative entries
+	_, err = c.Geocode("NOWHERE,TX")
+	assert.ErrorIs(t, err, geo.ErrNotFound)
+	assert.NoError(t, file.Close())
+	file, err = cached.OpenFile(path, 0)
+	assert.NoError(t, err)
+	defer file.Close()
+	c = cached.Geocoder(g, file, cached.WithForwardTTL(20*time.Millisecond), cached.WithStaleWhileRevalidate(time.Hour))
+	loc, err = c.Geocode("42, Some Street, Austin, Texas")
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, loc.Lng)
+	_, err = c.Geocode("NOWHERE,TX")
+	assert.ErrorIs(t, err, geo.ErrNotFound)
+	assert.EqualValues(t, 3, g.calls.Load())
+}
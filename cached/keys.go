@@ -0,0 +1,91 @@
+package cached
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// ExactLocation keys reverse lookups on the location to the microdegree, the default
+func ExactLocation(lat, lng float64) string {
+	return fmt.Sprintf("geo.Location{%f,%f}", lat, lng)
+}
+
+// NormalizeAddress keys forward lookups on address regardless of case, accents, whitespace and punctuation,
+// so that "64 Elizabeth St." and " 64  elizabeth st" share an entry
+func NormalizeAddress(address string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range norm.NFKD.String(address) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// accents, split from their letter by the decomposition
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// geohashAlphabet is the base 32 alphabet of geohashes
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Geohash keys reverse lookups on the geohash of precision characters of the location, from 1 to 12.
+// Precision 7 is a cell of about 150m by 150m, 8 of about 40m by 20m.
+func Geohash(precision int) func(lat, lng float64) string {
+	precision = min(max(1, precision), 12)
+	return func(lat, lng float64) string {
+		hash := make([]byte, precision)
+		latRange, lngRange := [2]float64{-90, 90}, [2]float64{-180, 180}
+		even := true
+		for i := range hash {
+			var c byte
+			for range 5 {
+				r, v := &latRange, lat
+				if even {
+					r, v = &lngRange, lng
+				}
+				c <<= 1
+				if mid := (r[0] + r[1]) / 2; v >= mid {
+					c |= 1
+					r[0] = mid
+				} else {
+					r[1] = mid
+				}
+				even = !even
+			}
+			hash[i] = geohashAlphabet[c]
+		}
+		return "geohash:" + string(hash)
+	}
+}
+
+// metresPerDegree is the length of a degree of latitude
+const metresPerDegree = 111320
+
+// Grid keys reverse lookups on the cell of a grid of squares about metres wide holding the location.
+// It panics unless metres is positive.
+func Grid(metres float64) func(lat, lng float64) string {
+	if !(metres > 0) {
+		panic(fmt.Sprintf("cached: Grid cells must be a positive number of metres wide, not %g", metres))
+	}
+	dLat := metres / metresPerDegree
+	return func(lat, lng float64) string {
+		row := math.Floor(lat / dLat)
+		// cells keep their width in metres away from the equator, spanning more degrees of longitude
+		dLng := 360.0
+		if c := math.Cos((row + 0.5) * dLat * math.Pi / 180); c > 0 {
+			dLng = math.Min(360, dLat/c)
+		}
+		return fmt.Sprintf("grid:%g:%d,%d", metres, int64(row), int64(math.Floor(lng/dLng)))
+	}
+}
@@ -0,0 +1,82 @@
+package cached
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// LRU is a Cache of bounded size, evicting the value used least recently to make room for a new one
+type LRU struct {
+	size       int
+	defaultTTL time.Duration
+
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+}
+
+// lruItem is the element of the LRU list
+type lruItem struct {
+	key string
+	entry
+}
+
+// NewLRU returns an LRU holding up to size values, set for defaultTTL unless told otherwise, 0 for ever
+func NewLRU(size int, defaultTTL time.Duration) *LRU {
+	return &LRU{size: max(1, size), defaultTTL: defaultTTL, order: list.New(), items: map[string]*list.Element{}}
+}
+
+// Get returns the value of key, unless it expired
+func (c *LRU) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	item := e.Value.(*lruItem)
+	if item.expired(time.Now()) {
+		c.remove(e)
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return item.value, true
+}
+
+// Set sets the value of key for ttl, evicting the value used least recently if the cache is full
+func (c *LRU) Set(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item := &lruItem{key: key, entry: newEntry(value, ttl, c.defaultTTL)}
+	if e, ok := c.items[key]; ok {
+		e.Value = item
+		c.order.MoveToFront(e)
+		return
+	}
+	c.items[key] = c.order.PushFront(item)
+	if c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete removes the value of key
+func (c *LRU) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.remove(e)
+	}
+}
+
+// Len returns how many values the cache holds, some of which may have expired
+func (c *LRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRU) remove(e *list.Element) {
+	c.order.Remove(e)
+	delete(c.items, e.Value.(*lruItem).key)
+}
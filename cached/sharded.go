@@ -0,0 +1,90 @@
+package cached
+
+import (
+	"hash/maphash"
+	"sync"
+	"time"
+)
+
+// DefaultShards is how many shards NewSharded splits a cache into unless told otherwise
+const DefaultShards = 32
+
+// Sharded is an unbounded Cache split into shards locked separately, so that concurrent lookups seldom wait on each other
+type Sharded struct {
+	seed       maphash.Seed
+	defaultTTL time.Duration
+	shards     []shard
+}
+
+type shard struct {
+	mu      sync.RWMutex
+	entries map[string]entry
+}
+
+// NewSharded returns a Sharded cache of shards shards, DefaultShards if 0, holding values for defaultTTL
+// unless told otherwise, 0 for ever. Expired values are only removed when read or by DeleteExpired.
+func NewSharded(shards int, defaultTTL time.Duration) *Sharded {
+	if shards <= 0 {
+		shards = DefaultShards
+	}
+	c := &Sharded{seed: maphash.MakeSeed(), defaultTTL: defaultTTL, shards: make([]shard, shards)}
+	for i := range c.shards {
+		c.shards[i].entries = map[string]entry{}
+	}
+	return c
+}
+
+func (c *Sharded) shard(key string) *shard {
+	return &c.shards[maphash.String(c.seed, key)%uint64(len(c.shards))]
+}
+
+// Get returns the value of key, unless it expired
+func (c *Sharded) Get(key string) (any, bool) {
+	s := c.shard(key)
+	s.mu.RLock()
+	e, ok := s.entries[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if e.expired(time.Now()) {
+		s.mu.Lock()
+		if e, ok := s.entries[key]; ok && e.expired(time.Now()) {
+			delete(s.entries, key)
+		}
+		s.mu.Unlock()
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set sets the value of key for ttl
+func (c *Sharded) Set(key string, value any, ttl time.Duration) {
+	s := c.shard(key)
+	s.mu.Lock()
+	s.entries[key] = newEntry(value, ttl, c.defaultTTL)
+	s.mu.Unlock()
+}
+
+// Delete removes the value of key
+func (c *Sharded) Delete(key string) {
+	s := c.shard(key)
+	s.mu.Lock()
+	delete(s.entries, key)
+	s.mu.Unlock()
+}
+
+// DeleteExpired removes every expired value, one shard at a time
+func (c *Sharded) DeleteExpired() {
+	now := time.Now()
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		for key, e := range s.entries {
+			if e.expired(now) {
+				delete(s.entries, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
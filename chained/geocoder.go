@@ -1,38 +1,145 @@
 package chained
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/codingsince1985/geo-golang"
 )
 
-type chainedGeocoder struct{ Geocoders []geo.Geocoder }
+// NotFoundPolicy decides what a chain does when a link finds nothing
+type NotFoundPolicy int
+
+const (
+	// FallThrough asks the next link of the chain
+	FallThrough NotFoundPolicy = iota
+	// Stop ends the chain, trusting the link that nothing is there to find
+	Stop
+)
+
+// Link is a geocoder of a chain, with how the chain treats it
+type Link struct {
+	Geocoder geo.Geocoder
+	// Name identifies the link in errors and answers, the provider of a geo.HTTPGeocoder by default
+	Name       string
+	OnNotFound NotFoundPolicy
+}
+
+// Chain looks up addresses and locations by each of its links in turn, until one of them answers
+type Chain struct{ links []Link }
 
 // Geocoder creates a chain of Geocoders to lookup address and fallback on
-func Geocoder(geocoders ...geo.Geocoder) geo.Geocoder { return chainedGeocoder{Geocoders: geocoders} }
-
-// Geocode returns location for address
-func (c chainedGeocoder) Geocode(address string) (*geo.Location, error) {
-	// Geocode address by each geocoder until we get a real location response
-	for i := range c.Geocoders {
-		if l, err := c.Geocoders[i].Geocode(address); err == nil && l != nil {
-			return l, nil
-		}
-		// skip error and try the next geocoder
-		continue
+func Geocoder(geocoders ...geo.Geocoder) geo.Geocoder {
+	links := make([]Link, len(geocoders))
+	for i, g := range geocoders {
+		links[i] = Link{Geocoder: g}
+	}
+	return New(links...)
+}
+
+// New creates a chain of links, naming those without a name after their provider or their position
+func New(links ...Link) *Chain {
+	c := &Chain{links: make([]Link, len(links))}
+	for i, l := range links {
+		l.Name = geo.MemberName(l.Geocoder, l.Name, fmt.Sprintf("link %d", i+1))
+		c.links[i] = l
 	}
-	// No geocoders found a result
-	return nil, nil
+	return c
 }
 
-// ReverseGeocode returns address for location
-func (c chainedGeocoder) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
-	// Geocode address by each geocoder until we get a real location response
-	for i := range c.Geocoders {
-		if addr, err := c.Geocoders[i].ReverseGeocode(lat, lng); err == nil && addr != nil {
-			return addr, nil
+// Geocode returns location for address, giving each link geo.DefaultTimeout to answer
+func (c *Chain) Geocode(address string) (*geo.Location, error) {
+	loc, _, err := c.geocode(context.Background(), geo.DefaultTimeout, address)
+	return loc, err
+}
+
+// GeocodeContext returns location for address, giving up on the rest of the chain once ctx is done
+func (c *Chain) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	loc, _, err := c.GeocodeWithProvider(ctx, address)
+	return loc, err
+}
+
+// GeocodeWithProvider returns location for address along with the name of the link that found it.
+// When no link does, the error joins the error of each link asked, named after it.
+func (c *Chain) GeocodeWithProvider(ctx context.Context, address string) (*geo.Location, string, error) {
+	return c.geocode(ctx, 0, address)
+}
+
+func (c *Chain) geocode(ctx context.Context, timeout time.Duration, address string) (*geo.Location, string, error) {
+	return first(ctx, c, timeout, func(ctx context.Context, g geo.Geocoder) (*geo.Location, error) {
+		return geo.AsContextGeocoder(g).GeocodeContext(ctx, address)
+	})
+}
+
+// GeocodeAddress returns location for a structured address, giving each link geo.DefaultTimeout to answer
+func (c *Chain) GeocodeAddress(address geo.Address) (*geo.Location, error) {
+	return c.geocodeAddress(context.Background(), geo.DefaultTimeout, address)
+}
+
+// GeocodeAddressContext returns location for a structured address, giving up on the rest of the chain once ctx is done.
+// Geocoders without structured lookups are sent the single line form of the address.
+func (c *Chain) GeocodeAddressContext(ctx context.Context, address geo.Address) (*geo.Location, error) {
+	return c.geocodeAddress(ctx, 0, address)
+}
+
+func (c *Chain) geocodeAddress(ctx context.Context, timeout time.Duration, address geo.Address) (*geo.Location, error) {
+	loc, _, err := first(ctx, c, timeout, func(ctx context.Context, g geo.Geocoder) (*geo.Location, error) {
+		return geo.AsStructuredGeocoder(g).GeocodeAddressContext(ctx, address)
+	})
+	return loc, err
+}
+
+// ReverseGeocode returns address for location, giving each link geo.DefaultTimeout to answer
+func (c *Chain) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
+	addr, _, err := c.reverseGeocode(context.Background(), geo.DefaultTimeout, lat, lng)
+	return addr, err
+}
+
+// ReverseGeocodeContext returns address for location, giving up on the rest of the chain once ctx is done
+func (c *Chain) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	addr, _, err := c.ReverseGeocodeWithProvider(ctx, lat, lng)
+	return addr, err
+}
+
+// ReverseGeocodeWithProvider returns address for location along with the name of the link that found it.
+// When no link does, the error joins the error of each link asked, named after it.
+func (c *Chain) ReverseGeocodeWithProvider(ctx context.Context, lat, lng float64) (*geo.Address, string, error) {
+	return c.reverseGeocode(ctx, 0, lat, lng)
+}
+
+func (c *Chain) reverseGeocode(ctx context.Context, timeout time.Duration, lat, lng float64) (*geo.Address, string, error) {
+	return first(ctx, c, timeout, func(ctx context.Context, g geo.Geocoder) (*geo.Address, error) {
+		return geo.AsContextGeocoder(g).ReverseGeocodeContext(ctx, lat, lng)
+	})
+}
+
+// first returns the first answer of the links of c to lookup, with the name of the link giving it.
+// Each link is given timeout to answer, unless it is 0.
+func first[T any](ctx context.Context, c *Chain, timeout time.Duration, lookup func(context.Context, geo.Geocoder) (*T, error)) (*T, string, error) {
+	var errs []error
+	for _, l := range c.links {
+		if ctx.Err() != nil {
+			return nil, "", geo.ContextError(ctx)
+		}
+		v, err := geo.WithinTimeout(ctx, timeout, func(ctx context.Context) (*T, error) { return lookup(ctx, l.Geocoder) })
+		if err == nil && v != nil {
+			return v, l.Name, nil
 		}
-		// skip error and try the next geocoder
-		continue
+		if err == nil {
+			err = geo.ErrNotFound
+		}
+		errs = append(errs, geo.MemberError(l.Name, err))
+		if l.OnNotFound == Stop && errors.Is(err, geo.ErrNotFound) {
+			break
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, "", geo.ContextError(ctx)
+	}
+	if len(errs) == 0 {
+		return nil, "", geo.ErrNotFound
 	}
-	// No geocoders found a result
-	return nil, nil
+	return nil, "", errors.Join(errs...)
 }
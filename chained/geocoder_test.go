@@ -1,12 +1,17 @@
 package chained_test
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/codingsince1985/geo-golang"
 	"github.com/codingsince1985/geo-golang/chained"
 	"github.com/codingsince1985/geo-golang/data"
+	"github.com/codingsince1985/geo-golang/openstreetmap"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -41,7 +46,7 @@ var (
 func TestGeocode(t *testing.T) {
 	location, err := geocoder.Geocode(addressFixture.FormattedAddress)
 	assert.NoError(t, err)
-	assert.Equal(t, geo.Location{locationFixture.Lat, locationFixture.Lng}, *location)
+	assert.Equal(t, geo.Location{Lat: locationFixture.Lat, Lng: locationFixture.Lng}, *location)
 }
 
 func TestReverseGeocode(t *testing.T) {
@@ -52,7 +57,7 @@ func TestReverseGeocode(t *testing.T) {
 
 func TestReverseGeocodeWithNoResult(t *testing.T) {
 	addr, err := geocoder.ReverseGeocode(0, 0)
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
 
@@ -82,6 +87,93 @@ func TestChainedGeocode(t *testing.T) {
 	assert.Equal(t, geo.Location{Lat: 3, Lng: 4}, *l)
 
 	addr, err := c.Geocode("NOWHERE,TX")
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
+
+func TestGeocodeContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	l, err := geocoder.(geo.ContextGeocoder).GeocodeContext(ctx, addressFixture.FormattedAddress)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, l)
+}
+
+// deadlineGeocoder records the deadline of its lookups, failing them with err after a while
+type deadlineGeocoder struct {
+	geo.Geocoder
+	deadline time.Time
+	err      error
+}
+
+func (g *deadlineGeocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	g.deadline, _ = ctx.Deadline()
+	if g.err != nil {
+		time.Sleep(10 * time.Millisecond)
+		return nil, g.err
+	}
+	return &locationFixture, nil
+}
+
+func (g *deadlineGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	return nil, geo.ErrNotFound
+}
+
+func TestGeocodeTimeoutPerLink(t *testing.T) {
+	// a link running out of time leaves the next one the whole of its own
+	slow, next := &deadlineGeocoder{err: geo.ErrTimeout}, &deadlineGeocoder{}
+	l, err := chained.Geocoder(slow, next).Geocode(addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, locationFixture, *l)
+	assert.WithinDuration(t, time.Now().Add(geo.DefaultTimeout), next.deadline, time.Second)
+	assert.True(t, next.deadline.After(slow.deadline))
+}
+
+func TestChainedGeocodeWithProvider(t *testing.T) {
+	c := chained.New(
+		chained.Link{Geocoder: data.Geocoder(data.AddressToLocation{}, data.LocationToAddress{}), Name: "empty"},
+		chained.Link{Geocoder: data.Geocoder(data.AddressToLocation{addressFixture: locationFixture}, data.LocationToAddress{})},
+	)
+
+	l, provider, err := c.GeocodeWithProvider(context.Background(), addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, locationFixture, *l)
+	assert.Equal(t, "link 2", provider)
+}
+
+func TestChainedGeocodeErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	c := chained.Geocoder(
+		openstreetmap.GeocoderWithURL(ts.URL+"/"),
+		data.Geocoder(data.AddressToLocation{}, data.LocationToAddress{}),
+	)
+	l, err := c.Geocode(addressFixture.FormattedAddress)
+	assert.Nil(t, l)
+	assert.ErrorIs(t, err, geo.ErrUnauthorized)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
+	assert.Contains(t, err.Error(), "openstreetmap: unauthorized")
+	assert.Contains(t, err.Error(), "link 2: not found")
+}
+
+func TestChainedStopOnNotFound(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		requests++
+		resp.Write([]byte(`[{"lat":"-37.814107","lon":"144.96328"}]`))
+	}))
+	defer ts.Close()
+
+	c := chained.New(
+		chained.Link{Geocoder: data.Geocoder(data.AddressToLocation{}, data.LocationToAddress{}), Name: "authoritative", OnNotFound: chained.Stop},
+		chained.Link{Geocoder: openstreetmap.GeocoderWithURL(ts.URL + "/")},
+	)
+	_, _, err := c.GeocodeWithProvider(context.Background(), "NOWHERE,TX")
+	assert.ErrorIs(t, err, geo.ErrNotFound)
+	assert.Equal(t, "authoritative: not found", err.Error())
+	assert.Equal(t, 0, requests)
+}
@@ -0,0 +1,238 @@
+// Package consensus asks several geocoders for the same address and keeps the location most of them agree on
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+// DefaultRadius is how many metres apart two locations may be and still agree, unless WithRadius says otherwise
+const DefaultRadius = 100
+
+// ErrNoConsensus is returned when too few geocoders agree on a location
+var ErrNoConsensus = errors.New("no consensus")
+
+// Option configures a Consensus
+type Option func(*Consensus)
+
+// WithRadius sets how many metres apart two locations may be and still agree
+func WithRadius(metres float64) Option { return func(c *Consensus) { c.radius = metres } }
+
+// WithThreshold flags results with a location further than metres from the consensus, the radius by default
+func WithThreshold(metres float64) Option { return func(c *Consensus) { c.threshold = metres } }
+
+// WithMinAgreement fails lookups with ErrNoConsensus when the agreement of the result is below share
+func WithMinAgreement(share float64) Option { return func(c *Consensus) { c.minAgreement = share } }
+
+// WithNames names the geocoders in answers and errors, in the order they were given.
+// Geocoders without a name are named after the provider of a geo.HTTPGeocoder or their position.
+func WithNames(names ...string) Option { return func(c *Consensus) { c.names = names } }
+
+// Answer is the location a geocoder found
+type Answer struct {
+	Name string
+	geo.Location
+	// Distance is how many metres the location is from the consensus
+	Distance float64
+}
+
+// Result is the location geocoders agree on
+type Result struct {
+	// Location is the centroid of the locations of the largest group of geocoders agreeing with each other
+	geo.Location
+	// Agreement is the share of the geocoders asked that agree on the location, failures counting against it
+	Agreement float64
+	Agreeing  []Answer
+	Outliers  []Answer
+	// Disagree is set when a geocoder found a location further than the threshold from the consensus
+	Disagree bool
+	// Err joins the errors of the geocoders that found nothing, named after them
+	Err error
+}
+
+// Consensus cross-checks the locations found by its geocoders
+type Consensus struct {
+	geocoders    []geo.Geocoder
+	names        []string
+	radius       float64
+	threshold    float64
+	minAgreement float64
+}
+
+// Geocoder looks up addresses by every geocoder and answers with the location most of them agree on.
+// Reverse lookups are answered by the first geocoder finding an address.
+func Geocoder(geocoders ...geo.Geocoder) geo.Geocoder { return New(geocoders) }
+
+// New returns a Consensus of geocoders
+func New(geocoders []geo.Geocoder, opts ...Option) *Consensus {
+	c := &Consensus{geocoders: geocoders, radius: DefaultRadius}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.threshold <= 0 {
+		c.threshold = c.radius
+	}
+
+	names := make([]string, len(geocoders))
+	for i, g := range geocoders {
+		if i < len(c.names) {
+			names[i] = c.names[i]
+		}
+		names[i] = geo.MemberName(g, names[i], fmt.Sprintf("geocoder %d", i+1))
+	}
+	c.names = names
+	return c
+}
+
+// Geocode returns location for address
+func (c *Consensus) Geocode(address string) (*geo.Location, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), geo.DefaultTimeout)
+	defer cancel()
+
+	return c.GeocodeContext(ctx, address)
+}
+
+// GeocodeContext returns the location the geocoders agree on for address
+func (c *Consensus) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	result, err := c.GeocodeConsensus(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	return &result.Location, nil
+}
+
+// GeocodeConsensus asks every geocoder for address at once and returns the location they agree on,
+// with how much they do. It fails when no geocoder finds address, or too few agree.
+func (c *Consensus) GeocodeConsensus(ctx context.Context, address string) (*Result, error) {
+	locations := make([]*geo.Location, len(c.geocoders))
+	errs := make([]error, len(c.geocoders))
+	var wg sync.WaitGroup
+	for i, g := range c.geocoders {
+		wg.Go(func() {
+			locations[i], errs[i] = geo.AsContextGeocoder(g).GeocodeContext(ctx, address)
+			if errs[i] == nil && locations[i] == nil {
+				errs[i] = geo.ErrNotFound
+			}
+			if errs[i] != nil {
+				errs[i] = geo.MemberError(c.names[i], errs[i])
+			}
+		})
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return nil, geo.ContextError(ctx)
+	}
+
+	var answers []Answer
+	for i, loc := range locations {
+		if loc != nil {
+			answers = append(answers, Answer{Name: c.names[i], Location: *loc})
+		}
+	}
+	if len(answers) == 0 {
+		if len(c.geocoders) == 0 {
+			return nil, geo.ErrNotFound
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	result := c.agree(answers)
+	result.Err = errors.Join(errs...)
+	if result.Agreement < c.minAgreement {
+		return result, fmt.Errorf("%w: %d of %d geocoders agree", ErrNoConsensus, len(result.Agreeing), len(c.geocoders))
+	}
+	return result, nil
+}
+
+// agree finds the largest group of answers within the radius of one of them, and the centroid of the group
+func (c *Consensus) agree(answers []Answer) *Result {
+	var agreeing []int
+	for _, a := range answers {
+		var near []int
+		for j, b := range answers {
+			if geo.Distance(a.Location, b.Location) <= c.radius {
+				near = append(near, j)
+			}
+		}
+		if len(near) > len(agreeing) {
+			agreeing = near
+		}
+	}
+
+	locations := make([]geo.Location, len(agreeing))
+	for i, j := range agreeing {
+		locations[i] = answers[j].Location
+	}
+	result := &Result{
+		Location:  centroid(locations),
+		Agreement: float64(len(agreeing)) / float64(len(c.geocoders)),
+	}
+
+	in := make([]bool, len(answers))
+	for _, j := range agreeing {
+		in[j] = true
+	}
+	for i, a := range answers {
+		a.Distance = geo.Distance(result.Location, a.Location)
+		if a.Distance > c.threshold {
+			result.Disagree = true
+		}
+		if in[i] {
+			result.Agreeing = append(result.Agreeing, a)
+		} else {
+			result.Outliers = append(result.Outliers, a)
+		}
+	}
+	return result
+}
+
+// centroid returns the location in the middle of locations, averaged on the sphere so that it holds across the antimeridian
+func centroid(locations []geo.Location) geo.Location {
+	var x, y, z float64
+	for _, l := range locations {
+		lat, lng := l.Lat*math.Pi/180, l.Lng*math.Pi/180
+		x += math.Cos(lat) * math.Cos(lng)
+		y += math.Cos(lat) * math.Sin(lng)
+		z += math.Sin(lat)
+	}
+	return geo.Location{
+		Lat: math.Atan2(z, math.Hypot(x, y)) * 180 / math.Pi,
+		Lng: math.Atan2(y, x) * 180 / math.Pi,
+	}
+}
+
+// ReverseGeocode returns address for location
+func (c *Consensus) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), geo.DefaultTimeout)
+	defer cancel()
+
+	return c.ReverseGeocodeContext(ctx, lat, lng)
+}
+
+// ReverseGeocodeContext returns the address found by the first geocoder finding one, as addresses can't be averaged.
+// When none does, the error joins the error of each geocoder, named after it.
+func (c *Consensus) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	var errs []error
+	for i, g := range c.geocoders {
+		addr, err := geo.AsContextGeocoder(g).ReverseGeocodeContext(ctx, lat, lng)
+		if err == nil && addr != nil {
+			return addr, nil
+		}
+		if ctx.Err() != nil {
+			return nil, geo.ContextError(ctx)
+		}
+		if err == nil {
+			err = geo.ErrNotFound
+		}
+		errs = append(errs, geo.MemberError(c.names[i], err))
+	}
+	if len(errs) == 0 {
+		return nil, geo.ErrNotFound
+	}
+	return nil, errors.Join(errs...)
+}
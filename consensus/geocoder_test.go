@@ -0,0 +1,102 @@
+package consensus_test
+
+import (
+	"testing"
+
+	"github.com/codingsince1985/geo-golang"
+	"github.com/codingsince1985/geo-golang/consensus"
+	"github.com/codingsince1985/geo-golang/data"
+	"github.com/stretchr/testify/assert"
+)
+
+const address = "64 Elizabeth Street, Melbourne, Victoria 3000, Australia"
+
+func at(lat, lng float64) geo.Geocoder {
+	loc := geo.Location{Lat: lat, Lng: lng}
+	addr := geo.Address{FormattedAddress: address}
+	return data.Geocoder(data.AddressToLocation{addr: loc}, data.LocationToAddress{loc: addr})
+}
+
+var empty = data.Geocoder(data.AddressToLocation{}, data.LocationToAddress{})
+
+func TestGeocodeConsensus(t *testing.T) {
+	c := consensus.New([]geo.Geocoder{
+		at(-37.8141, 144.9633),
+		at(-37.8142, 144.9632),
+		at(-33.8688, 151.2093),
+		empty,
+	}, consensus.WithNames("a", "b", "sydney", "empty"))
+
+	result, err := c.GeocodeConsensus(t.Context(), address)
+	assert.NoError(t, err)
+	assert.InDelta(t, -37.81415, result.Lat, 1e-6)
+	assert.InDelta(t, 144.96325, result.Lng, 1e-6)
+	assert.Equal(t, 0.5, result.Agreement)
+	assert.Len(t, result.Agreeing, 2)
+	assert.Equal(t, "a", result.Agreeing[0].Name)
+	assert.Less(t, result.Agreeing[0].Distance, 10.0)
+	assert.Len(t, result.Outliers, 1)
+	assert.Equal(t, "sydney", result.Outliers[0].Name)
+	assert.Greater(t, result.Outliers[0].Distance, 700000.0)
+	assert.True(t, result.Disagree)
+	assert.ErrorIs(t, result.Err, geo.ErrNotFound)
+	assert.Contains(t, result.Err.Error(), "empty: not found")
+}
+
+func TestAgreement(t *testing.T) {
+	result, err := consensus.New([]geo.Geocoder{at(-37.8141, 144.9633), at(-37.8146, 144.9636)}).
+		GeocodeConsensus(t.Context(), address)
+	assert.NoError(t, err)
+	assert.Len(t, result.Agreeing, 2)
+	assert.Equal(t, 1.0, result.Agreement)
+	assert.False(t, result.Disagree)
+
+	// the locations are 127m apart, too far for the default radius
+	result, err = consensus.New([]geo.Geocoder{at(-37.8141, 144.9633), at(-37.8151, 144.9640)}).
+		GeocodeConsensus(t.Context(), address)
+	assert.NoError(t, err)
+	assert.Len(t, result.Agreeing, 1)
+	assert.Equal(t, 0.5, result.Agreement)
+	assert.True(t, result.Disagree)
+
+	// unless they are within the threshold
+	result, err = consensus.New([]geo.Geocoder{at(-37.8141, 144.9633), at(-37.8151, 144.9640)}, consensus.WithThreshold(200)).
+		GeocodeConsensus(t.Context(), address)
+	assert.NoError(t, err)
+	assert.False(t, result.Disagree)
+}
+
+func TestMinAgreement(t *testing.T) {
+	c := consensus.New([]geo.Geocoder{at(-37.8141, 144.9633), at(-33.8688, 151.2093), empty}, consensus.WithMinAgreement(0.5))
+	loc, err := c.Geocode(address)
+	assert.Nil(t, loc)
+	assert.ErrorIs(t, err, consensus.ErrNoConsensus)
+	assert.EqualError(t, err, "no consensus: 1 of 3 geocoders agree")
+}
+
+func TestAntimeridian(t *testing.T) {
+	loc, err := consensus.Geocoder(at(0, 179.9998), at(0, -179.9998)).Geocode(address)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0, loc.Lat, 1e-9)
+	assert.InDelta(t, 180, abs(loc.Lng), 1e-9)
+}
+
+func TestNotFound(t *testing.T) {
+	loc, err := consensus.Geocoder(empty, empty).Geocode(address)
+	assert.Nil(t, loc)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
+	assert.Contains(t, err.Error(), "geocoder 2: not found")
+}
+
+func TestReverseGeocode(t *testing.T) {
+	addr, err := consensus.Geocoder(empty, at(-37.8141, 144.9633)).ReverseGeocode(-37.8141, 144.9633)
+	assert.NoError(t, err)
+	assert.Equal(t, address, addr.FormattedAddress)
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
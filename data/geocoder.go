@@ -1,6 +1,8 @@
 package data
 
 import (
+	"context"
+
 	"github.com/codingsince1985/geo-golang"
 )
 
@@ -26,6 +28,14 @@ func Geocoder(addressToLocation AddressToLocation, LocationToAddress LocationToA
 
 // Geocode returns location for address
 func (d dataGeocoder) Geocode(address string) (*geo.Location, error) {
+	return d.GeocodeContext(context.Background(), address)
+}
+
+// GeocodeContext returns location for address unless ctx is already done, or ErrNotFound for an unknown address
+func (d dataGeocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	if ctx.Err() != nil {
+		return nil, geo.ContextError(ctx)
+	}
 	addr := geo.Address{
 		FormattedAddress: address,
 	}
@@ -33,13 +43,38 @@ func (d dataGeocoder) Geocode(address string) (*geo.Location, error) {
 		return &l, nil
 	}
 
-	return nil, nil
+	return nil, geo.ErrNotFound
+}
+
+// GeocodeAddress returns location for a structured address
+func (d dataGeocoder) GeocodeAddress(address geo.Address) (*geo.Location, error) {
+	return d.GeocodeAddressContext(context.Background(), address)
+}
+
+// GeocodeAddressContext returns location for an address stored with exactly the same fields,
+// falling back to one stored under its single line form
+func (d dataGeocoder) GeocodeAddressContext(ctx context.Context, address geo.Address) (*geo.Location, error) {
+	if ctx.Err() != nil {
+		return nil, geo.ContextError(ctx)
+	}
+	if l, ok := d.AddressToLocation[address]; ok {
+		return &l, nil
+	}
+	return d.GeocodeContext(ctx, address.SingleLine())
 }
 
 // ReverseGeocode returns address for location
 func (d dataGeocoder) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
+	return d.ReverseGeocodeContext(context.Background(), lat, lng)
+}
+
+// ReverseGeocodeContext returns address for location unless ctx is already done, or ErrNotFound for an unknown location
+func (d dataGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	if ctx.Err() != nil {
+		return nil, geo.ContextError(ctx)
+	}
 	if address, ok := d.LocationToAddress[geo.Location{Lat: lat, Lng: lng}]; ok {
 		return &address, nil
 	}
-	return nil, nil
+	return nil, geo.ErrNotFound
 }
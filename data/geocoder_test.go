@@ -33,6 +33,17 @@ func TestGeocode(t *testing.T) {
 	assert.Equal(t, geo.Location{Lat: -37.814107, Lng: 144.96328}, *location)
 }
 
+func TestGeocodeAddress(t *testing.T) {
+	g := geocoder.(geo.StructuredGeocoder)
+	location, err := g.GeocodeAddress(addressFixture)
+	assert.NoError(t, err)
+	assert.Equal(t, locationFixture, *location)
+
+	location, err = g.GeocodeAddress(geo.Address{Street: "Nowhere St"})
+	assert.ErrorIs(t, err, geo.ErrNotFound)
+	assert.Nil(t, location)
+}
+
 func TestReverseGeocode(t *testing.T) {
 	address, err := geocoder.ReverseGeocode(locationFixture.Lat, locationFixture.Lng)
 	assert.Nil(t, err)
@@ -42,6 +53,6 @@ func TestReverseGeocode(t *testing.T) {
 
 func TestReverseGeocodeWithNoResult(t *testing.T) {
 	addr, err := geocoder.ReverseGeocode(1, 2)
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
@@ -0,0 +1,34 @@
+package geo
+
+import "math"
+
+// earthRadius is the mean radius of the Earth in metres
+const earthRadius = 6371008.8
+
+// Distance returns the great circle distance in metres between two locations
+func Distance(from, to Location) float64 {
+	lat1, lat2 := radians(from.Lat), radians(to.Lat)
+	dLat, dLng := lat2-lat1, radians(to.Lng-from.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BoundsAround returns the smallest box containing every location within radius metres of center
+func BoundsAround(center Location, radius float64) BoundingBox {
+	dLat := degrees(radius / earthRadius)
+	dLng := 180.0
+	if c := math.Cos(radians(center.Lat)); c > 0 {
+		dLng = math.Min(180, dLat/c)
+	}
+	return BoundingBox{
+		South: math.Max(-90, center.Lat-dLat),
+		West:  math.Max(-180, center.Lng-dLng),
+		North: math.Min(90, center.Lat+dLat),
+		East:  math.Min(180, center.Lng+dLng),
+	}
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
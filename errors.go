@@ -0,0 +1,149 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Sentinel errors reported by geocoders. Callers can test for them with errors.Is
+// to decide whether to retry, fall back to another provider or alert.
+var (
+	// ErrNotFound occurs when the provider has no result for the query
+	ErrNotFound = errors.New("not found")
+	// ErrQuotaExceeded occurs when a rate limit or usage quota of the provider is exhausted
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrUnauthorized occurs when the provider rejects the credentials
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrInvalidRequest occurs when the provider rejects the query itself
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrProviderUnavailable occurs when the provider fails or cannot be reached
+	ErrProviderUnavailable = errors.New("provider unavailable")
+	// ErrUnsupported occurs when a geocoder cannot do what was asked, e.g. honour a search filter
+	ErrUnsupported = errors.New("unsupported")
+)
+
+// maxBodyExcerpt is the most bytes of a failed response body kept in a ProviderError
+const maxBodyExcerpt = 256
+
+// ProviderError wraps one of the sentinel errors with the provider that reported it
+// and the raw status the provider used.
+// Errors for non-2xx HTTP responses also carry the response metadata and the start of the body.
+type ProviderError struct {
+	Provider string
+	Status   string
+	Err      error
+	Response *ResponseMeta
+	Body     string
+}
+
+// StatusError returns a ProviderError for a raw provider status mapped onto err.
+// HTTPGeocoder fills in the provider name.
+func StatusError(status string, err error) error {
+	return &ProviderError{Status: status, Err: err}
+}
+
+func (e *ProviderError) Error() string {
+	msg := e.Err.Error()
+	if e.Status != "" {
+		msg += " (" + e.Status + ")"
+	}
+	if e.Provider != "" {
+		msg = e.Provider + ": " + msg
+	}
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+// RetryAfter returns how long the provider asked clients to wait before trying again, 0 if it did not say
+func (e *ProviderError) RetryAfter() time.Duration {
+	if e.Response == nil {
+		return 0
+	}
+	return e.Response.RetryAfter
+}
+
+func (e *ProviderError) Unwrap() error { return e.Err }
+
+// HTTPStatusError maps a non-2xx HTTP status code onto a sentinel error, or returns nil for a successful one
+func HTTPStatusError(code int) error {
+	switch {
+	case code >= 200 && code < 300:
+		return nil
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrUnauthorized
+	case code == http.StatusTooManyRequests || code == http.StatusPaymentRequired:
+		return ErrQuotaExceeded
+	case code == http.StatusNotFound:
+		return ErrNotFound
+	case code >= 500:
+		return ErrProviderUnavailable
+	default:
+		return ErrInvalidRequest
+	}
+}
+
+// NamedError names the provider in err if it is, or wraps, one of the sentinel errors.
+// A ProviderError without a provider is left as is, as others may hold it too, and a copy naming provider returned.
+func NamedError(provider string, err error) error {
+	var pe *ProviderError
+	if errors.As(err, &pe) {
+		if pe.Provider != "" {
+			return err
+		}
+		if err == error(pe) {
+			named := *pe
+			named.Provider = provider
+			return &named
+		}
+		return &ProviderError{Provider: provider, Err: err, Response: pe.Response}
+	}
+	for _, sentinel := range []error{ErrNotFound, ErrQuotaExceeded, ErrUnauthorized, ErrInvalidRequest, ErrProviderUnavailable, ErrUnsupported} {
+		if errors.Is(err, sentinel) {
+			return &ProviderError{Provider: provider, Err: err}
+		}
+	}
+	return err
+}
+
+// MemberError names the geocoder err comes from, one of several asked in turn or at once, unless err already does.
+// An error naming another provider, e.g. that of a geocoder wrapped by the member, is prefixed with name instead.
+func MemberError(name string, err error) error {
+	var pe *ProviderError
+	if errors.As(err, &pe) && pe.Provider != "" && pe.Provider != name {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if err = NamedError(name, err); errors.As(err, &pe) {
+		return err
+	}
+	return fmt.Errorf("%s: %w", name, err)
+}
+
+// ResponseError returns a ProviderError for a non-2xx HTTP response, or nil for a successful one.
+// body is what was read of the response, only its start is kept.
+func ResponseError(resp *http.Response, body []byte) error {
+	err := HTTPStatusError(resp.StatusCode)
+	if err == nil {
+		return nil
+	}
+	meta := ParseResponseMeta(resp)
+	return &ProviderError{Status: resp.Status, Err: err, Response: &meta, Body: excerpt(body)}
+}
+
+// excerpt returns the start of body on a single line, cut at a rune boundary
+func excerpt(body []byte) string {
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if len(s) <= maxBodyExcerpt {
+		return s
+	}
+	cut := maxBodyExcerpt
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
@@ -0,0 +1,75 @@
+package geo
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Filter narrows down the results of forward geocoding
+type Filter struct {
+	// Countries restricts results to these ISO 3166-1 alpha-2 country codes
+	Countries []string
+	// Bounds biases results toward this box, or restricts them to it if RestrictToBounds is set
+	Bounds           *BoundingBox
+	RestrictToBounds bool
+	// Proximity biases results toward this location
+	Proximity *Location
+}
+
+// IsZero reports whether f filters nothing
+func (f Filter) IsZero() bool {
+	return len(f.Countries) == 0 && f.Bounds == nil && f.Proximity == nil
+}
+
+// WithCountries restricts results to the given ISO 3166-1 alpha-2 country codes
+func WithCountries(codes ...string) Option {
+	return func(o *Options) { o.Filter.Countries = codes }
+}
+
+// WithBoundsBias prefers results inside box
+func WithBoundsBias(box BoundingBox) Option {
+	return func(o *Options) { o.Filter.Bounds, o.Filter.RestrictToBounds = &box, false }
+}
+
+// WithBoundsRestriction only accepts results inside box
+func WithBoundsRestriction(box BoundingBox) Option {
+	return func(o *Options) { o.Filter.Bounds, o.Filter.RestrictToBounds = &box, true }
+}
+
+// WithProximity prefers results close to l
+func WithProximity(l Location) Option {
+	return func(o *Options) { o.Filter.Proximity = &l }
+}
+
+// FilterEndpointBuilder is implemented by EndpointBuilders whose provider can narrow down forward geocoding.
+// FilterURL adds the parameters for f to rawURL, or fails with UnsupportedError for a part of f it cannot honour.
+type FilterEndpointBuilder interface {
+	FilterURL(rawURL string, f Filter) (string, error)
+}
+
+// UnsupportedError reports that a provider cannot honour what was asked, e.g. a filter
+func UnsupportedError(what string) error {
+	return fmt.Errorf("%w: %s", ErrUnsupported, what)
+}
+
+// SetParams sets params in the query of rawURL, replacing parameters of the same name.
+// The rest of the query is kept as built by the EndpointBuilder rather than re-encoded.
+func SetParams(rawURL string, params url.Values) string {
+	if len(params) == 0 {
+		return rawURL
+	}
+
+	base, query, hasQuery := strings.Cut(rawURL, "?")
+	var kept []string
+	if hasQuery {
+		for _, p := range strings.Split(query, "&") {
+			name, _, _ := strings.Cut(p, "=")
+			if _, replaced := params[name]; p != "" && !replaced {
+				kept = append(kept, p)
+			}
+		}
+	}
+	kept = append(kept, params.Encode())
+	return base + "?" + strings.Join(kept, "&")
+}
@@ -2,7 +2,11 @@
 package frenchapigouv
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
+	"mime/multipart"
+	"net/http"
 	"strings"
 
 	"github.com/codingsince1985/geo-golang"
@@ -40,14 +44,22 @@ type (
 		Licence     string
 		Query       string
 		Limit       int
+
+		// rows of the CSV answer to a batch request, by column name
+		rows []map[string]string
 	}
-	context struct {
+	// unmarshaler decodes the CSV answer to a batch request and JSON otherwise
+	unmarshaler struct{}
+	context     struct {
 		state      string
 		county     string
 		countyCode string
 	}
 )
 
+// batchSize is the most addresses sent in one CSV file, well below the 50 MB the API accepts
+const batchSize = 5000
+
 // Geocoder constructs FrenchApiGouv geocoder
 func Geocoder() geo.Geocoder { return GeocoderWithURL("https://api-adresse.data.gouv.fr/") }
 
@@ -56,11 +68,48 @@ func GeocoderWithURL(url string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(url),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		ResponseUnmarshaler:   unmarshaler{},
+		Provider:              "frenchapigouv",
 	}
 }
 
-func (b baseURL) GeocodeURL(address string) string {
-	return string(b) + "search?limit=1&q=" + address
+func (b baseURL) GeocodeURL(address string) string { return b.GeocodeAllURL(address, 1) }
+
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return string(b) + fmt.Sprintf("search?limit=%d&q=", limit) + address
+}
+
+// SuggestURL asks the search endpoint to complete the last word of the query
+func (b baseURL) SuggestURL(partial string, opts geo.SuggestOptions) string {
+	u := b.GeocodeAllURL(partial, opts.Limit) + "&autocomplete=1"
+	if p := opts.Proximity; p != nil {
+		u += fmt.Sprintf("&lat=%f&lon=%f", p.Lat, p.Lng)
+	}
+	return u
+}
+
+func (b baseURL) BatchSize() int { return batchSize }
+
+// BatchRequest uploads addresses as a CSV file of a single column q
+func (b baseURL) BatchRequest(addresses []string) geo.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField("columns", "q")
+	file, _ := form.CreateFormFile("data", "addresses.csv")
+	w := csv.NewWriter(file)
+	w.Write([]string{"q"})
+	for _, a := range addresses {
+		w.Write([]string{a})
+	}
+	w.Flush()
+	form.Close()
+
+	return geo.Request{
+		Method:      http.MethodPost,
+		URL:         string(b) + "search/csv/",
+		ContentType: form.FormDataContentType(),
+		Body:        body.Bytes(),
+	}
 }
 
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
@@ -78,6 +127,97 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 	}, nil
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	var results []geo.Result
+	for _, s := range r.suggestions() {
+		results = append(results, *s.Result)
+	}
+	return results, nil
+}
+
+func (r *geocodeResponse) Suggestions() ([]geo.Suggestion, error) { return r.suggestions(), nil }
+
+// suggestions returns a suggestion for every feature with coordinates, carrying its result
+func (r *geocodeResponse) suggestions() []geo.Suggestion {
+	var suggestions []geo.Suggestion
+	for _, f := range r.Features {
+		p := f.Geometry.Coordinates
+		if len(p) < 2 {
+			continue
+		}
+		suggestions = append(suggestions, geo.Suggestion{
+			ID:   f.Properties.ID,
+			Text: f.Properties.Label,
+			Result: &geo.Result{
+				Location:         geo.Location{Lat: p[1], Lng: p[0]},
+				FormattedAddress: f.Properties.Label,
+				PlaceType:        f.Properties.Type,
+				Precision:        banPrecision(f.Properties.Type),
+				Confidence:       f.Properties.Score,
+			},
+		})
+	}
+	return suggestions
+}
+
+// BatchResults reads the result columns the API appends to every row of the CSV file sent
+func (r *geocodeResponse) BatchResults(n int) ([]geo.BatchResult, error) {
+	results := make([]geo.BatchResult, len(r.rows))
+	for i, row := range r.rows {
+		switch status := row["result_status"]; {
+		case row["latitude"] == "" || status == "not-found":
+			results[i].Err = geo.ErrNotFound
+		case status != "" && status != "ok":
+			results[i].Err = geo.StatusError(status, geo.ErrInvalidRequest)
+		default:
+			results[i].Result = &geo.Result{
+				Location:         geo.Location{Lat: geo.ParseFloat(row["latitude"]), Lng: geo.ParseFloat(row["longitude"])},
+				FormattedAddress: row["result_label"],
+				PlaceType:        row["result_type"],
+				Precision:        banPrecision(row["result_type"]),
+				Confidence:       geo.ParseFloat(row["result_score"]),
+			}
+		}
+	}
+	return results, nil
+}
+
+func (unmarshaler) Unmarshal(data []byte, v any) error {
+	r, ok := v.(*geocodeResponse)
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("\ufeff"))
+	if !ok || len(data) == 0 || data[0] == '{' || data[0] == '[' {
+		return (&geo.JSONUnmarshaler{}).Unmarshal(data, v)
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil || len(records) == 0 {
+		return err
+	}
+	header := records[0]
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				row[name] = record[i]
+			}
+		}
+		r.rows = append(r.rows, row)
+	}
+	return nil
+}
+
+func banPrecision(typ string) geo.Precision {
+	switch typ {
+	case "housenumber":
+		return geo.PrecisionRooftop
+	case "street", "locality":
+		return geo.PrecisionStreet
+	case "municipality":
+		return geo.PrecisionCity
+	}
+	return geo.PrecisionUnknown
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
 	if len(r.Features) == 0 || r.Features[0].Properties.Label == "baninfo" {
 		return nil, nil
@@ -1,6 +1,9 @@
 package frenchapigouv_test
 
 import (
+	"context"
+	"io"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -22,13 +25,61 @@ func TestGeocode(t *testing.T) {
 	assert.Equal(t, geo.Location{Lat: 48.859831, Lng: 2.328123}, *location)
 }
 
+func TestSuggest(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		resp.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	geocoder := frenchapigouv.GeocoderWithURL(ts.URL + "/").(geo.Suggester)
+	suggestions, err := geocoder.Suggest(context.Background(), "5 Quai Anatole", geo.SuggestOptions{Limit: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, "1", query.Get("autocomplete"))
+	assert.Equal(t, "3", query.Get("limit"))
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, "ADRNIVX_0000000270768224", suggestions[0].ID)
+	assert.Equal(t, "5 Quai Anatole France 75007 Paris", suggestions[0].Text)
+
+	ts.Close()
+	result, err := geocoder.Resolve(context.Background(), suggestions[0])
+	assert.Nil(t, err)
+	assert.Equal(t, geo.Location{Lat: 48.859831, Lng: 2.328123}, result.Location)
+	assert.Equal(t, geo.PrecisionRooftop, result.Precision)
+}
+
+func TestGeocodeBatch(t *testing.T) {
+	var path, columns, data string
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		path, columns = req.URL.Path, req.FormValue("columns")
+		if f, _, err := req.FormFile("data"); err == nil {
+			b, _ := io.ReadAll(f)
+			data = string(b)
+		}
+		resp.Write([]byte(responseCSV))
+	}))
+	defer ts.Close()
+
+	geocoder := frenchapigouv.GeocoderWithURL(ts.URL + "/").(geo.BatchGeocoder)
+	results, err := geocoder.GeocodeBatch(context.Background(), []string{"5 Quai Anatole France, Paris", "nowhere"})
+	assert.Nil(t, err)
+	assert.Equal(t, "/search/csv/", path)
+	assert.Equal(t, "q", columns)
+	assert.Equal(t, "q\n\"5 Quai Anatole France, Paris\"\nnowhere\n", data)
+	assert.Len(t, results, 2)
+	assert.Equal(t, geo.Location{Lat: 48.859831, Lng: 2.328123}, results[0].Result.Location)
+	assert.Equal(t, geo.PrecisionRooftop, results[0].Result.Precision)
+	assert.ErrorIs(t, results[1].Err, geo.ErrNotFound)
+}
+
 func TestGeocodeWithNoResult(t *testing.T) {
 	ts := testServer(response2)
 	defer ts.Close()
 
 	geocoder := frenchapigouv.GeocoderWithURL(ts.URL + "/")
 	location, err := geocoder.Geocode("nowhere")
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, location)
 }
 
@@ -73,7 +124,7 @@ func TestReverseGeocodeWithNoResult(t *testing.T) {
 	geocoder := frenchapigouv.GeocoderWithURL(ts.URL + "/")
 	addr, err := geocoder.ReverseGeocode(0, 0.34)
 	assert.Nil(t, addr)
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 }
 
 func TestReverseGeocodeWithNoResultByDefaultPoints(t *testing.T) {
@@ -83,7 +134,7 @@ func TestReverseGeocodeWithNoResultByDefaultPoints(t *testing.T) {
 	geocoder := frenchapigouv.GeocoderWithURL(ts.URL + "/")
 	addr, err := geocoder.ReverseGeocode(0, 0)
 	assert.Nil(t, addr)
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 }
 
 func testServer(response string) *httptest.Server {
@@ -93,6 +144,9 @@ func testServer(response string) *httptest.Server {
 }
 
 const (
+	responseCSV = "\ufeffq,latitude,longitude,result_label,result_score,result_type,result_status\r\n" +
+		"\"5 Quai Anatole France, Paris\",48.859831,2.328123,5 Quai Anatole France 75007 Paris,0.97,housenumber,ok\r\n" +
+		"nowhere,,,,,,not-found\r\n"
 	response1 = `[
 		{
 			 "type": "FeatureCollection",
@@ -1,7 +1,9 @@
 package geocod
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
 
 	geo "github.com/codingsince1985/geo-golang"
@@ -25,15 +27,25 @@ type (
 				Lat float64
 				Lng float64
 			}
+			Accuracy     float64
+			AccuracyType string `json:"accuracy_type"`
+
+			// Batch responses wrap the response to each address instead
+			Response *geocodeResponse `json:"response"`
 		}
+		Error string `json:"error"`
 	}
 )
 
+// batchSize is the most addresses a batch request may carry
+const batchSize = 10000
+
 // Geocoder constructs Geocodio geocoder
 func Geocoder(key string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getUrl(key, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "geocod",
 	}
 }
 
@@ -44,12 +56,27 @@ func getUrl(key string, baseURLs ...string) string {
 	return "https://api.geocod.io/v1/*&api_key=" + key
 }
 
-func (b baseURL) GeocodeURL(address string) string {
-	params := fmt.Sprintf("geocode?q=%s", address)
+func (b baseURL) GeocodeURL(address string) string { return b.GeocodeAllURL(address, 1) }
+
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	params := fmt.Sprintf("geocode?q=%s&limit=%d", address, limit)
 	url := strings.Replace(string(b), "*", params, 1)
 	return url
 }
 
+func (b baseURL) BatchSize() int { return batchSize }
+
+// BatchRequest posts addresses as a JSON array
+func (b baseURL) BatchRequest(addresses []string) geo.Request {
+	body, _ := json.Marshal(addresses)
+	return geo.Request{
+		Method:      http.MethodPost,
+		URL:         strings.Replace(string(b), "*", "geocode?limit=1", 1),
+		ContentType: "application/json",
+		Body:        body,
+	}
+}
+
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 	params := fmt.Sprintf("reverse?q=%f,%f", l.Lat, l.Lng)
 	url := strings.Replace(string(b), "*", params, 1)
@@ -65,6 +92,52 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 	return &geo.Location{Lat: loc.Lat, Lng: loc.Lng}, nil
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	results := make([]geo.Result, 0, len(r.Results))
+	for _, res := range r.Results {
+		results = append(results, geo.Result{
+			Location:         geo.Location{Lat: res.Location.Lat, Lng: res.Location.Lng},
+			FormattedAddress: res.Address,
+			PlaceType:        res.AccuracyType,
+			Precision:        geocodPrecision(res.AccuracyType),
+			Confidence:       res.Accuracy,
+		})
+	}
+	return results, nil
+}
+
+func (r *geocodeResponse) BatchResults(n int) ([]geo.BatchResult, error) {
+	results := make([]geo.BatchResult, len(r.Results))
+	for i, res := range r.Results {
+		switch {
+		case res.Response == nil:
+			results[i].Err = geo.ErrProviderUnavailable
+		case res.Response.Error != "":
+			results[i].Err = geo.StatusError(res.Response.Error, geo.ErrInvalidRequest)
+		case len(res.Response.Results) == 0:
+			results[i].Err = geo.ErrNotFound
+		default:
+			candidates, _ := res.Response.Candidates()
+			results[i].Result = &candidates[0]
+		}
+	}
+	return results, nil
+}
+
+func geocodPrecision(accuracyType string) geo.Precision {
+	switch accuracyType {
+	case "rooftop", "point", "nearest_rooftop_match":
+		return geo.PrecisionRooftop
+	case "range_interpolation", "intersection", "street_center", "nearest_street":
+		return geo.PrecisionStreet
+	case "place":
+		return geo.PrecisionCity
+	case "county", "state":
+		return geo.PrecisionRegion
+	}
+	return geo.PrecisionUnknown
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
 	if len(r.Results) == 0 {
 		return nil, nil
@@ -1,6 +1,9 @@
 package geocod
 
 import (
+	"context"
+	"errors"
+	"io"
 	"math"
 	"net/http"
 	"net/http/httptest"
@@ -61,6 +64,35 @@ func TestReverseGeocode(t *testing.T) {
 	}
 }
 
+func TestGeocodeBatch(t *testing.T) {
+	var method, body string
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		data, _ := io.ReadAll(req.Body)
+		method, body = req.Method, string(data)
+		resp.Write([]byte(batchResp))
+	}))
+	defer ts.Close()
+
+	geocoder := Geocoder(key, ts.URL+"/*").(geo.BatchGeocoder)
+	results, err := geocoder.GeocodeBatch(context.Background(), []string{"1109 N Highland St, Arlington VA", "nowhere"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if method != http.MethodPost || body != `["1109 N Highland St, Arlington VA","nowhere"]` {
+		t.Fatalf("Got: %s %s\n", method, body)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Got: %d results\tExpected: 2\n", len(results))
+	}
+	if results[0].Err != nil || math.Abs(results[0].Result.Location.Lat-38.886665) > eps {
+		t.Fatalf("Got: %+v\n", results[0])
+	}
+	if !errors.Is(results[1].Err, geo.ErrNotFound) {
+		t.Fatalf("Got: %v\tExpected: %v\n", results[1].Err, geo.ErrNotFound)
+	}
+}
+
 func testServer(response string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		resp.Write([]byte(response))
@@ -70,6 +102,11 @@ func testServer(response string) *httptest.Server {
 const (
 	eps = 1.0e-5
 
+	batchResp = `{"results":[
+		{"query":"1109 N Highland St, Arlington VA","response":{"results":[{"formatted_address":"1109 N Highland St, Arlington, VA 22201","location":{"lat":38.886665,"lng":-77.094733},"accuracy":1,"accuracy_type":"rooftop"}]}},
+		{"query":"nowhere","response":{"results":[]}}
+	]}`
+
 	geocodeResp = `
 {
   "input": {
@@ -2,8 +2,11 @@
 package geo
 
 import (
+	"context"
 	"io"
 	"log"
+	"strings"
+	"time"
 )
 
 // Geocoder can look up (lat, long) by address and address by (lat, long)
@@ -12,11 +15,222 @@ type Geocoder interface {
 	ReverseGeocode(lat, lng float64) (*Address, error)
 }
 
+// ContextGeocoder is a Geocoder whose lookups honour the deadline and cancellation of a context
+type ContextGeocoder interface {
+	Geocoder
+	GeocodeContext(ctx context.Context, address string) (*Location, error)
+	ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*Address, error)
+}
+
+// MultiGeocoder can look up several candidate results for an ambiguous address.
+// Candidates are ordered best match first and there are at most limit of them.
+type MultiGeocoder interface {
+	GeocodeAll(address string, limit int) ([]Result, error)
+	GeocodeAllContext(ctx context.Context, address string, limit int) ([]Result, error)
+}
+
+// StructuredGeocoder can look up a location from an address whose fields are kept apart,
+// rather than flattened into a single line the provider has to parse again
+type StructuredGeocoder interface {
+	GeocodeAddress(address Address) (*Location, error)
+	GeocodeAddressContext(ctx context.Context, address Address) (*Location, error)
+}
+
+// AsContextGeocoder returns g itself if it is a ContextGeocoder.
+// Otherwise g is wrapped so that a lookup returns as soon as ctx is done,
+// leaving the underlying call to finish in the background.
+func AsContextGeocoder(g Geocoder) ContextGeocoder {
+	if cg, ok := g.(ContextGeocoder); ok {
+		return cg
+	}
+	return contextGeocoder{g}
+}
+
+// WithinTimeout calls f with ctx bounded by timeout, unless it is 0 or less,
+// e.g. to give each of the lookups a wrapper sends on a deadline of its own
+func WithinTimeout[T any](ctx context.Context, timeout time.Duration, f func(context.Context) (T, error)) (T, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return f(ctx)
+}
+
+// AsStructuredGeocoder returns g itself if it is a StructuredGeocoder.
+// Otherwise the returned geocoder looks up the single line form of an address.
+func AsStructuredGeocoder(g Geocoder) StructuredGeocoder {
+	if sg, ok := g.(StructuredGeocoder); ok {
+		return sg
+	}
+	return structuredGeocoder{AsContextGeocoder(g)}
+}
+
+// Wrapper is implemented by geocoders sending their lookups to a single other geocoder, e.g. to cache or retry them
+type Wrapper interface {
+	Unwrap() Geocoder
+}
+
+// ProviderName returns the name of the provider g sends its lookups to, looking through the Wrappers around it,
+// or "" if it doesn't end up at an HTTPGeocoder
+func ProviderName(g Geocoder) string {
+	for {
+		switch v := g.(type) {
+		case HTTPGeocoder:
+			return v.Provider
+		case Wrapper:
+			g = v.Unwrap()
+		default:
+			return ""
+		}
+	}
+}
+
+// MemberName returns name, unless it is empty, the name of the provider of g, unless it has none, or fallback,
+// e.g. the position of g among the geocoders of a chain
+func MemberName(g Geocoder, name, fallback string) string {
+	if name != "" {
+		return name
+	}
+	if provider := ProviderName(g); provider != "" {
+		return provider
+	}
+	return fallback
+}
+
+// GeocodeBest returns the best candidate for address of a MultiGeocoder, or the location other geocoders find.
+// Finding nothing is ErrNotFound.
+func GeocodeBest(ctx context.Context, g Geocoder, address string) (*Result, error) {
+	if mg, ok := g.(MultiGeocoder); ok {
+		results, err := mg.GeocodeAllContext(ctx, address, 1)
+		if err == nil && len(results) == 0 {
+			err = ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &results[0], nil
+	}
+
+	loc, err := AsContextGeocoder(g).GeocodeContext(ctx, address)
+	if err == nil && loc == nil {
+		err = ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Location: *loc}, nil
+}
+
+type structuredGeocoder struct{ ContextGeocoder }
+
+func (g structuredGeocoder) Unwrap() Geocoder { return g.ContextGeocoder }
+
+func (g structuredGeocoder) GeocodeAddress(address Address) (*Location, error) {
+	return g.Geocode(address.SingleLine())
+}
+
+func (g structuredGeocoder) GeocodeAddressContext(ctx context.Context, address Address) (*Location, error) {
+	return g.GeocodeContext(ctx, address.SingleLine())
+}
+
+type contextGeocoder struct{ Geocoder }
+
+func (g contextGeocoder) Unwrap() Geocoder { return g.Geocoder }
+
+func (g contextGeocoder) GeocodeContext(ctx context.Context, address string) (*Location, error) {
+	type geoResp struct {
+		l *Location
+		e error
+	}
+	ch := make(chan geoResp, 1)
+
+	go func() {
+		l, e := g.Geocode(address)
+		ch <- geoResp{l: l, e: e}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ContextError(ctx)
+	case res := <-ch:
+		return res.l, res.e
+	}
+}
+
+func (g contextGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*Address, error) {
+	type revResp struct {
+		a *Address
+		e error
+	}
+	ch := make(chan revResp, 1)
+
+	go func() {
+		a, e := g.ReverseGeocode(lat, lng)
+		ch <- revResp{a: a, e: e}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ContextError(ctx)
+	case res := <-ch:
+		return res.a, res.e
+	}
+}
+
 // Location is the output of Geocode
 type Location struct {
 	Lat, Lng float64
 }
 
+// Result is a geocoding candidate along with what the provider reports about its quality
+type Result struct {
+	Location
+	FormattedAddress string
+	// PlaceType is the provider's own name for the kind of place found, e.g. "street_address" or "locality"
+	PlaceType string
+	Precision Precision
+	// Confidence is the provider's match score scaled to 0..1, or 0 if it doesn't report one
+	Confidence float64
+	// BoundingBox is the area covered by the place, or nil if the provider doesn't report one
+	BoundingBox *BoundingBox
+}
+
+// Precision is how finely a Result pins down a place, from country level up to a single building.
+// Precisions are ordered, so r.Precision >= PrecisionStreet accepts street level results and better.
+type Precision int
+
+// Precision levels, coarsest first
+const (
+	PrecisionUnknown Precision = iota
+	PrecisionCountry
+	PrecisionRegion
+	PrecisionCity
+	PrecisionStreet
+	PrecisionRooftop
+)
+
+func (p Precision) String() string {
+	switch p {
+	case PrecisionCountry:
+		return "country"
+	case PrecisionRegion:
+		return "region"
+	case PrecisionCity:
+		return "city"
+	case PrecisionStreet:
+		return "street"
+	case PrecisionRooftop:
+		return "rooftop"
+	}
+	return "unknown"
+}
+
+// BoundingBox is a rectangular area bounded by two parallels and two meridians
+type BoundingBox struct {
+	South, West, North, East float64
+}
+
 // Address is returned by ReverseGeocode.
 // This is a structured representation of an address, including its flat representation
 type Address struct {
@@ -34,6 +248,37 @@ type Address struct {
 	City             string
 }
 
+// SingleLine returns FormattedAddress if it is set, otherwise the address fields joined into one line
+func (a Address) SingleLine() string {
+	if a.FormattedAddress != "" {
+		return a.FormattedAddress
+	}
+
+	state := a.State
+	if state == "" {
+		state = a.StateCode
+	}
+	country := a.Country
+	if country == "" {
+		country = a.CountryCode
+	}
+
+	var parts []string
+	for _, p := range []string{
+		strings.TrimSpace(a.HouseNumber + " " + a.Street),
+		a.Suburb,
+		a.City,
+		a.County,
+		strings.TrimSpace(state + " " + a.Postcode),
+		country,
+	} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
 // ErrLogger is an implementation of StdLogger that geo uses to log its error messages.
 var ErrLogger StdLogger = log.New(io.Discard, "[Geo][Err]", log.LstdFlags)
 
@@ -5,6 +5,7 @@ go 1.26.2
 require (
 	github.com/patrickmn/go-cache v2.1.0+incompatible
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/text v0.42.0
 )
 
 require (
@@ -4,6 +4,8 @@ package google
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/codingsince1985/geo-golang"
 )
@@ -12,12 +14,25 @@ type (
 	baseURL         string
 	geocodeResponse struct {
 		Results []struct {
+			// Name and PlaceID are only set in Places text search responses
+			Name              string                   `json:"name"`
+			PlaceID           string                   `json:"place_id"`
 			FormattedAddress  string                   `json:"formatted_address"`
 			AddressComponents []googleAddressComponent `json:"address_components"`
 			Geometry          struct {
-				Location geo.Location
+				Location     geo.Location
+				LocationType string `json:"location_type"`
+				Viewport     struct {
+					Northeast, Southwest geo.Location
+				}
 			}
+			Types []string `json:"types"`
 		}
+		// Places Autocomplete response
+		Predictions []struct {
+			Description string `json:"description"`
+			PlaceID     string `json:"place_id"`
+		} `json:"predictions"`
 		Status string `json:"status"`
 	}
 	googleAddressComponent struct {
@@ -38,6 +53,10 @@ const (
 	componentTypeState         = "administrative_area_level_1"
 	componentTypeCountry       = "country"
 	componentTypePostcode      = "postal_code"
+	locationTypeRooftop        = "ROOFTOP"
+	locationTypeInterpolated   = "RANGE_INTERPOLATED"
+	// suggestBiasRadius is the radius in metres around a proximity that suggestions are biased toward
+	suggestBiasRadius = 50000
 )
 
 // Geocoder constructs Google geocoder
@@ -45,6 +64,7 @@ func Geocoder(apiKey string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getURL(apiKey, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "google",
 	}
 }
 
@@ -57,25 +77,215 @@ func getURL(apiKey string, baseURLs ...string) string {
 
 func (b baseURL) GeocodeURL(address string) string { return string(b) + "address=" + address }
 
+// StructuredGeocodeURL sends the street line as the address and restricts the match
+// to the remaining fields through component filtering
+func (b baseURL) StructuredGeocodeURL(a geo.Address) string {
+	var components []string
+	for _, c := range []struct{ name, value string }{
+		{componentTypeLocality, a.City},
+		{"administrative_area", a.State},
+		{componentTypePostcode, a.Postcode},
+		{componentTypeCountry, a.CountryCode},
+	} {
+		if c.value != "" {
+			components = append(components, c.name+":"+c.value)
+		}
+	}
+	if a.CountryCode == "" && a.Country != "" {
+		components = append(components, componentTypeCountry+":"+a.Country)
+	}
+
+	v := url.Values{}
+	if street := strings.TrimSpace(a.HouseNumber + " " + a.Street); street != "" {
+		v.Set("address", street)
+	}
+	if len(components) > 0 {
+		v.Set("components", strings.Join(components, "|"))
+	}
+	return string(b) + v.Encode()
+}
+
+// SuggestURL uses Places Autocomplete, which sits next to the geocoding endpoint and always answers up to five predictions
+func (b baseURL) SuggestURL(partial string, opts geo.SuggestOptions) string {
+	u := strings.Replace(string(b), "/geocode/json", "/place/autocomplete/json", 1) + "input=" + partial
+	if p := opts.Proximity; p != nil {
+		u += fmt.Sprintf("&locationbias=circle:%d@%f,%f", suggestBiasRadius, p.Lat, p.Lng)
+	}
+	return u
+}
+
+// ResolveURL geocodes the place a prediction stands for by its place ID
+func (b baseURL) ResolveURL(s geo.Suggestion) string {
+	return string(b) + "place_id=" + url.QueryEscape(s.ID)
+}
+
+// PlaceSearchURL uses Places text search, which sits next to the geocoding endpoint like Places Autocomplete
+func (b baseURL) PlaceSearchURL(text string, q geo.PlaceQuery) string {
+	u := strings.Replace(string(b), "/geocode/json", "/place/textsearch/json", 1) + "query=" + text
+	if n := q.Near; n != nil {
+		u += fmt.Sprintf("&location=%f,%f&radius=%.0f", n.Lat, n.Lng, q.Radius)
+	}
+	return u
+}
+
+func (b baseURL) LanguageParams(language string) url.Values {
+	return url.Values{"language": {language}}
+}
+
+// FilterURL restricts to a country through components, next to those of a structured query, and biases toward bounds.
+// Google takes a single country, only biases toward bounds and has no notion of proximity.
+func (b baseURL) FilterURL(rawURL string, f geo.Filter) (string, error) {
+	switch {
+	case len(f.Countries) > 1:
+		return "", geo.UnsupportedError("more than one country")
+	case f.Bounds != nil && f.RestrictToBounds:
+		return "", geo.UnsupportedError("bounds restriction")
+	case f.Proximity != nil:
+		return "", geo.UnsupportedError("proximity")
+	}
+
+	params := url.Values{}
+	if len(f.Countries) == 1 {
+		components := componentTypeCountry + ":" + f.Countries[0]
+		if u, err := url.Parse(rawURL); err == nil && u.Query().Get("components") != "" {
+			components = u.Query().Get("components") + "|" + components
+		}
+		params.Set("components", components)
+		params.Set("region", strings.ToLower(f.Countries[0]))
+	}
+	if bb := f.Bounds; bb != nil {
+		params.Set("bounds", fmt.Sprintf("%f,%f|%f,%f", bb.South, bb.West, bb.North, bb.East))
+	}
+	return geo.SetParams(rawURL, params), nil
+}
+
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 	return string(b) + fmt.Sprintf("result_type=street_address&latlng=%f,%f", l.Lat, l.Lng)
 }
 
 func (r *geocodeResponse) Location() (*geo.Location, error) {
+	results, err := r.Candidates()
+	if len(results) == 0 {
+		return nil, err
+	}
+	return &results[0].Location, nil
+}
+
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]geo.Result, 0, len(r.Results))
+	for _, res := range r.Results {
+		result := geo.Result{
+			Location:         res.Geometry.Location,
+			FormattedAddress: res.FormattedAddress,
+			Precision:        googlePrecision(res.Geometry.LocationType, res.Types),
+		}
+		if len(res.Types) > 0 {
+			result.PlaceType = res.Types[0]
+		}
+		if v := res.Geometry.Viewport; v.Northeast != (geo.Location{}) || v.Southwest != (geo.Location{}) {
+			result.BoundingBox = &geo.BoundingBox{
+				South: v.Southwest.Lat,
+				West:  v.Southwest.Lng,
+				North: v.Northeast.Lat,
+				East:  v.Northeast.Lng,
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (r *geocodeResponse) Suggestions() ([]geo.Suggestion, error) {
 	if r.Status == statusNoResults {
 		return nil, nil
-	} else if r.Status != statusOK {
-		return nil, fmt.Errorf("geocoding error: %s", r.Status)
+	}
+	if err := r.err(); err != nil {
+		return nil, err
 	}
 
-	return &r.Results[0].Geometry.Location, nil
+	suggestions := make([]geo.Suggestion, 0, len(r.Predictions))
+	for _, p := range r.Predictions {
+		suggestions = append(suggestions, geo.Suggestion{ID: p.PlaceID, Text: p.Description})
+	}
+	return suggestions, nil
 }
 
-func (r *geocodeResponse) Address() (*geo.Address, error) {
+func (r *geocodeResponse) PlaceResults() ([]geo.Place, error) {
 	if r.Status == statusNoResults {
 		return nil, nil
-	} else if r.Status != statusOK {
-		return nil, fmt.Errorf("reverse geocoding error: %s", r.Status)
+	}
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+
+	places := make([]geo.Place, 0, len(r.Results))
+	for _, res := range r.Results {
+		place := geo.Place{
+			ID:       res.PlaceID,
+			Name:     res.Name,
+			Location: res.Geometry.Location,
+			Address:  res.FormattedAddress,
+		}
+		if len(res.Types) > 0 {
+			place.Category = res.Types[0]
+		}
+		places = append(places, place)
+	}
+	return places, nil
+}
+
+// statusErrors maps the status of a failed request onto a geo error
+var statusErrors = map[string]error{
+	statusNoResults:    geo.ErrNotFound,
+	"OVER_QUERY_LIMIT": geo.ErrQuotaExceeded,
+	"OVER_DAILY_LIMIT": geo.ErrQuotaExceeded,
+	"REQUEST_DENIED":   geo.ErrUnauthorized,
+	"INVALID_REQUEST":  geo.ErrInvalidRequest,
+	"UNKNOWN_ERROR":    geo.ErrProviderUnavailable,
+}
+
+func (r *geocodeResponse) err() error {
+	if r.Status == statusOK {
+		return nil
+	}
+	if err, ok := statusErrors[r.Status]; ok {
+		return geo.StatusError(r.Status, err)
+	}
+	return geo.StatusError(r.Status, geo.ErrProviderUnavailable)
+}
+
+// googlePrecision trusts location_type for addresses and falls back on the place types otherwise
+func googlePrecision(locationType string, types []string) geo.Precision {
+	switch locationType {
+	case locationTypeRooftop:
+		return geo.PrecisionRooftop
+	case locationTypeInterpolated:
+		return geo.PrecisionStreet
+	}
+	for _, typ := range types {
+		switch typ {
+		case "street_address", "premise", "subpremise", "point_of_interest", "establishment":
+			return geo.PrecisionRooftop
+		case "route", "intersection":
+			return geo.PrecisionStreet
+		case componentTypeLocality, componentTypeSuburb, componentTypePostcode, "neighborhood", "postal_town":
+			return geo.PrecisionCity
+		case componentTypeState, componentTypeStateDistrict:
+			return geo.PrecisionRegion
+		case componentTypeCountry:
+			return geo.PrecisionCountry
+		}
+	}
+	return geo.PrecisionUnknown
+}
+
+func (r *geocodeResponse) Address() (*geo.Address, error) {
+	if err := r.err(); err != nil {
+		return nil, err
 	}
 
 	if len(r.Results) == 0 || len(r.Results[0].AddressComponents) == 0 {
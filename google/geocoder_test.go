@@ -1,8 +1,11 @@
 package google_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
@@ -24,6 +27,101 @@ func TestGeocode(t *testing.T) {
 	assert.Equal(t, geo.Location{Lat: -37.8137683, Lng: 144.9718448}, *location)
 }
 
+func TestGeocodeAddress(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		resp.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	geocoder := google.Geocoder(token, ts.URL+"/?").(geo.StructuredGeocoder)
+	location, err := geocoder.GeocodeAddress(geo.Address{
+		HouseNumber: "60",
+		Street:      "Collins St",
+		City:        "Melbourne",
+		Postcode:    "3000",
+		CountryCode: "AU",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, geo.Location{Lat: -37.8137683, Lng: 144.9718448}, *location)
+	assert.Equal(t, "60 Collins St", query.Get("address"))
+	assert.Equal(t, "locality:Melbourne|postal_code:3000|country:AU", query.Get("components"))
+}
+
+func TestGeocodeWithLanguage(t *testing.T) {
+	var req *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, r *http.Request) {
+		req = r
+		resp.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	geocoder := geo.Configure(google.Geocoder(token, ts.URL+"/?"), geo.WithLanguage("fr"))
+	_, err := geocoder.Geocode("60 Collins St, Melbourne VIC 3000")
+	assert.NoError(t, err)
+	assert.Equal(t, "fr", req.URL.Query().Get("language"))
+	assert.Equal(t, "60 Collins St, Melbourne VIC 3000", req.URL.Query().Get("address"))
+	assert.Equal(t, "fr", req.Header.Get("Accept-Language"))
+}
+
+func TestGeocodeWithFilter(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		resp.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	geocoder := geo.Configure(google.Geocoder(token, ts.URL+"/?"),
+		geo.WithCountries("AU"),
+		geo.WithBoundsBias(geo.BoundingBox{South: -38, West: 144, North: -37, East: 145}),
+	).(geo.StructuredGeocoder)
+	_, err := geocoder.GeocodeAddress(geo.Address{Street: "60 Collins St", City: "Melbourne"})
+	assert.NoError(t, err)
+	assert.Equal(t, "locality:Melbourne|country:AU", query.Get("components"))
+	assert.Equal(t, "au", query.Get("region"))
+	assert.Equal(t, "-38.000000,144.000000|-37.000000,145.000000", query.Get("bounds"))
+
+	query = nil
+	_, err = geo.Configure(google.Geocoder(token, ts.URL+"/?"), geo.WithProximity(geo.Location{Lat: -37.8, Lng: 144.9})).
+		Geocode("60 Collins St, Melbourne VIC 3000")
+	assert.ErrorIs(t, err, geo.ErrUnsupported)
+	assert.Nil(t, query)
+}
+
+func TestGeocodeAll(t *testing.T) {
+	ts := testServer(response4)
+	defer ts.Close()
+
+	geocoder := google.Geocoder(token, ts.URL+"/").(geo.MultiGeocoder)
+	results, err := geocoder.GeocodeAll("Springfield", 5)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, geo.Location{Lat: 39.78172, Lng: -89.6501481}, results[0].Location)
+	assert.Equal(t, "Springfield, IL, USA", results[0].FormattedAddress)
+	assert.Equal(t, geo.PrecisionCity, results[0].Precision)
+	assert.Equal(t, geo.Location{Lat: 37.2089572, Lng: -93.2922989}, results[1].Location)
+}
+
+func TestGeocodeAllRooftop(t *testing.T) {
+	ts := testServer(response1)
+	defer ts.Close()
+
+	geocoder := google.Geocoder(token, ts.URL+"/").(geo.MultiGeocoder)
+	results, err := geocoder.GeocodeAll("60 Collins St, Melbourne VIC 3000", 1)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, geo.PrecisionRooftop, results[0].Precision)
+	assert.Equal(t, "street_address", results[0].PlaceType)
+	assert.Equal(t, &geo.BoundingBox{
+		South: -37.8151172802915,
+		West:  144.9704958197085,
+		North: -37.8124193197085,
+		East:  144.9731937802915,
+	}, results[0].BoundingBox)
+}
+
 func TestReverseGeocode(t *testing.T) {
 	ts := testServer(response2)
 	defer ts.Close()
@@ -42,10 +140,81 @@ func TestReverseGeocodeWithNoResult(t *testing.T) {
 
 	geocoder := google.Geocoder(token, ts.URL+"/")
 	addr, err := geocoder.ReverseGeocode(-37.8137683, 164.9718448)
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
 
+func TestSuggestAndResolve(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		if req.URL.Path == "/maps/api/place/autocomplete/json" {
+			resp.Write([]byte(responseAutocomplete))
+			return
+		}
+		resp.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	geocoder := google.Geocoder(token, ts.URL+"/maps/api/geocode/json?").(geo.Suggester)
+	suggestions, err := geocoder.Suggest(context.Background(), "60 Collins", geo.SuggestOptions{Limit: 1, Proximity: &geo.Location{Lat: -37.8, Lng: 144.9}})
+	assert.NoError(t, err)
+	assert.Equal(t, "60 Collins", query.Get("input"))
+	assert.Equal(t, "circle:50000@-37.800000,144.900000", query.Get("locationbias"))
+	assert.Equal(t, []geo.Suggestion{{ID: "ChIJOwg_06VC1moRYnU4Czs6rA4", Text: "60 Collins St, Melbourne VIC, Australia"}}, suggestions)
+
+	result, err := geocoder.Resolve(context.Background(), suggestions[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "ChIJOwg_06VC1moRYnU4Czs6rA4", query.Get("place_id"))
+	assert.Equal(t, geo.Location{Lat: -37.8137683, Lng: 144.9718448}, result.Location)
+
+	ts.Config.Handler = http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Write([]byte(`{"predictions": [], "status": "ZERO_RESULTS"}`))
+	})
+	suggestions, err = geocoder.Suggest(context.Background(), "xyzzy", geo.SuggestOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, suggestions)
+}
+
+func TestSearchPlaces(t *testing.T) {
+	var req *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, r *http.Request) {
+		req = r
+		resp.Write([]byte(responsePlaces))
+	}))
+	defer ts.Close()
+
+	near := geo.Location{Lat: -37.8136, Lng: 144.9631}
+	geocoder := google.Geocoder(token, ts.URL+"/maps/api/geocode/json?").(geo.PlaceSearcher)
+	places, err := geocoder.SearchPlaces(context.Background(), geo.PlaceQuery{Text: "coffee", Near: &near, Radius: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, "/maps/api/place/textsearch/json", req.URL.Path)
+	assert.Equal(t, "coffee", req.URL.Query().Get("query"))
+	assert.Equal(t, "-37.813600,144.963100", req.URL.Query().Get("location"))
+	assert.Equal(t, "1000", req.URL.Query().Get("radius"))
+	assert.Len(t, places, 1)
+	assert.Equal(t, "Brother Baba Budan", places[0].Name)
+	assert.Equal(t, "cafe", places[0].Category)
+	assert.Equal(t, "359 Little Bourke St, Melbourne VIC 3000, Australia", places[0].Address)
+	// Google doesn't report distances, so it is measured from Near
+	assert.InDelta(t, 188, places[0].Distance, 5)
+}
+
+func TestGeocodeOverQueryLimit(t *testing.T) {
+	ts := testServer(`{"results": [], "status": "OVER_QUERY_LIMIT"}`)
+	defer ts.Close()
+
+	geocoder := google.Geocoder(token, ts.URL+"/")
+	location, err := geocoder.Geocode("60 Collins St, Melbourne VIC 3000")
+	assert.Nil(t, location)
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+
+	var providerErr *geo.ProviderError
+	assert.True(t, errors.As(err, &providerErr))
+	assert.Equal(t, "google", providerErr.Provider)
+	assert.Equal(t, "OVER_QUERY_LIMIT", providerErr.Status)
+}
+
 func testServer(response string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		resp.Write([]byte(response))
@@ -458,5 +627,55 @@ const (
 	response3 = `{
    "results" : [],
    "status" : "ZERO_RESULTS"
+}`
+	response4 = `{
+   "results" : [
+      {
+         "formatted_address" : "Springfield, IL, USA",
+         "geometry" : {
+            "location" : { "lat" : 39.78172, "lng" : -89.6501481 },
+            "location_type" : "APPROXIMATE"
+         },
+         "types" : [ "locality", "political" ]
+      },
+      {
+         "formatted_address" : "Springfield, MO, USA",
+         "geometry" : {
+            "location" : { "lat" : 37.2089572, "lng" : -93.2922989 },
+            "location_type" : "APPROXIMATE"
+         },
+         "types" : [ "locality", "political" ]
+      }
+   ],
+   "status" : "OK"
+}`
+	responseAutocomplete = `{
+   "predictions" : [
+      {
+         "description" : "60 Collins St, Melbourne VIC, Australia",
+         "place_id" : "ChIJOwg_06VC1moRYnU4Czs6rA4",
+         "types" : [ "street_address", "geocode" ]
+      },
+      {
+         "description" : "60 Collins St, Hobart TAS, Australia",
+         "place_id" : "ChIJ3Q-Da3h1bqoRsP0m8rBpDwQ",
+         "types" : [ "street_address", "geocode" ]
+      }
+   ],
+   "status" : "OK"
+}`
+	responsePlaces = `{
+   "results" : [
+      {
+         "formatted_address" : "359 Little Bourke St, Melbourne VIC 3000, Australia",
+         "geometry" : {
+            "location" : { "lat" : -37.8133, "lng" : 144.9610 }
+         },
+         "name" : "Brother Baba Budan",
+         "place_id" : "ChIJ5Tq3Ak5d1moRqGJxBgtVVGU",
+         "types" : [ "cafe", "food", "point_of_interest", "establishment" ]
+      }
+   ],
+   "status" : "OK"
 }`
 )
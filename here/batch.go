@@ -0,0 +1,213 @@
+package here
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+const (
+	// batchSize is the most addresses submitted in one job, HERE accepts up to a million
+	batchSize = 10000
+	// firstPoll and lastPoll bound the wait between two checks of a job's status, which doubles from one to the next
+	firstPoll = 100 * time.Millisecond
+	lastPoll  = 5 * time.Second
+
+	batchParams = "&action=run&header=true&inDelim=;&outDelim=;&outputcompressed=false" +
+		"&outCols=displayLatitude,displayLongitude,locationLabel,relevance,matchLevel"
+)
+
+type (
+	batchGeocoder struct {
+		jobsURL, credentials string
+		options              geo.Options
+	}
+	jobResponse struct {
+		Response struct {
+			MetaInfo struct {
+				RequestID string `xml:"RequestId"`
+			}
+			Status string
+		}
+		Details string
+	}
+)
+
+// BatchGeocoder constructs a HERE geocoder for the Batch Geocoder API.
+// Addresses are submitted as a job, whose status is checked until its results can be downloaded.
+func BatchGeocoder(id, code string, baseURLs ...string) geo.BatchGeocoder {
+	jobsURL := "https://batch.geocoder.api.here.com/6.2/jobs"
+	if len(baseURLs) > 0 {
+		jobsURL = strings.TrimSuffix(baseURLs[0], "/")
+	}
+	return &batchGeocoder{jobsURL: jobsURL, credentials: "app_id=" + id + "&app_code=" + code}
+}
+
+// WithOptions returns a copy of the batch geocoder with opts applied.
+// It returns a geo.BatchGeocoder rather than a geo.Geocoder, as the Batch Geocoder API has no single lookups,
+// so the batch geocoder isn't geo.Configurable and geo.Configure leaves it unchanged.
+func (g *batchGeocoder) WithOptions(opts ...geo.Option) geo.BatchGeocoder {
+	c := *g
+	c.options = g.options.Apply(opts...)
+	return &c
+}
+
+// GeocodeBatch runs one job per batchSize addresses, waiting for each to complete.
+// Jobs can't narrow down their results, so it fails with geo.ErrUnsupported when a search filter is set.
+func (g *batchGeocoder) GeocodeBatch(ctx context.Context, addresses []string) ([]geo.BatchResult, error) {
+	if !g.options.Filter.IsZero() {
+		return nil, geo.NamedError("here", geo.UnsupportedError("search filters on batch geocoding"))
+	}
+	results := make([]geo.BatchResult, 0, len(addresses))
+	for start := 0; start < len(addresses); start += batchSize {
+		batch, err := g.run(ctx, addresses[start:min(start+batchSize, len(addresses))])
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, geo.ContextError(ctx)
+			}
+			return nil, geo.NamedError("here", err)
+		}
+		for i := range batch {
+			if batch[i].Err != nil {
+				batch[i].Err = geo.NamedError("here", batch[i].Err)
+			}
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+func (g *batchGeocoder) run(ctx context.Context, addresses []string) ([]geo.BatchResult, error) {
+	// the input is ; delimited with one address per line, so neither can appear inside an address
+	var body bytes.Buffer
+	body.WriteString("recId;searchText\n")
+	for i, a := range addresses {
+		a = strings.Join(strings.FieldsFunc(a, func(r rune) bool { return r == ';' || r == '\n' || r == '\r' }), ",")
+		fmt.Fprintf(&body, "%d;%s\n", i+1, a)
+	}
+
+	var job jobResponse
+	if err := g.fetchJob(ctx, http.MethodPost, g.jobsURL+"?"+g.credentials+batchParams, body.Bytes(), &job); err != nil {
+		return nil, err
+	}
+	id := job.Response.MetaInfo.RequestID
+	if id == "" {
+		return nil, geo.StatusError(job.Details, geo.ErrInvalidRequest)
+	}
+
+	for wait := firstPoll; job.Response.Status != "completed"; wait = min(2*wait, lastPoll) {
+		switch job.Response.Status {
+		case "failed", "cancelled", "deleted":
+			return nil, geo.StatusError("job "+id+" "+job.Response.Status, geo.ErrProviderUnavailable)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		if err := g.fetchJob(ctx, http.MethodGet, g.jobsURL+"/"+id+"?action=status&"+g.credentials, nil, &job); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := g.fetch(ctx, http.MethodGet, g.jobsURL+"/"+id+"/result?outputcompressed=false&"+g.credentials, nil)
+	if err != nil {
+		return nil, err
+	}
+	return batchResults(data, len(addresses))
+}
+
+func (g *batchGeocoder) fetchJob(ctx context.Context, method, url string, body []byte, job *jobResponse) error {
+	data, err := g.fetch(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(data, job)
+}
+
+func (g *batchGeocoder) fetch(ctx context.Context, method, url string, body []byte) ([]byte, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := g.options.NewRequest(ctx, method, url, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	}
+
+	resp, err := g.options.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := g.options.ReadBody(resp)
+	if statusErr := geo.ResponseError(resp, data); statusErr != nil {
+		return nil, g.options.Fail(resp.Request, statusErr)
+	}
+	if err != nil {
+		return nil, g.options.Fail(resp.Request, err)
+	}
+	return data, nil
+}
+
+// batchResults reads the output of a job, which may have many rows per recId: the first one is the best match.
+// Addresses that were not matched have a row with a seqLength of 0.
+func batchResults(data []byte, n int) ([]geo.BatchResult, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = ';'
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", geo.ErrProviderUnavailable, err)
+	}
+
+	results := make([]geo.BatchResult, n)
+	for i := range results {
+		results[i].Err = geo.ErrNotFound
+	}
+	if len(records) == 0 {
+		return results, nil
+	}
+
+	column := map[string]int{}
+	for i, name := range records[0] {
+		column[name] = i
+	}
+	field := func(record []string, name string) string {
+		if i, ok := column[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+	for _, record := range records[1:] {
+		i, err := strconv.Atoi(field(record, "recId"))
+		if err != nil || i < 1 || i > n || results[i-1].Result != nil || field(record, "seqLength") == "0" {
+			continue
+		}
+		lat, lng := field(record, "displayLatitude"), field(record, "displayLongitude")
+		if lat == "" || lng == "" {
+			continue
+		}
+		results[i-1] = geo.BatchResult{Result: &geo.Result{
+			Location:         geo.Location{Lat: geo.ParseFloat(lat), Lng: geo.ParseFloat(lng)},
+			FormattedAddress: field(record, "locationLabel"),
+			PlaceType:        field(record, "matchLevel"),
+			Precision:        matchLevelPrecision(field(record, "matchLevel")),
+			Confidence:       geo.ParseFloat(field(record, "relevance")),
+		}}
+	}
+	return results, nil
+}
@@ -3,6 +3,7 @@ package here
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/codingsince1985/geo-golang"
 )
@@ -13,10 +14,17 @@ type (
 		Response struct {
 			View []struct {
 				Result []struct {
-					Location struct {
+					Relevance  float64
+					MatchLevel string
+					Location   struct {
 						DisplayPosition struct {
 							Latitude, Longitude float64
 						}
+						MapView struct {
+							TopLeft, BottomRight struct {
+								Latitude, Longitude float64
+							}
+						}
 						Address struct {
 							Label          string
 							Country        string
@@ -59,6 +67,7 @@ func Geocoder(id, code string, radius int, baseURLs ...string) geo.Geocoder {
 			getGeocodeURL(p, baseURLs...),
 			getReverseGeocodeURL(p, baseURLs...)},
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "here",
 	}
 }
 
@@ -78,6 +87,14 @@ func getReverseGeocodeURL(p string, baseURLs ...string) string {
 
 func (b baseURL) GeocodeURL(address string) string { return b.forGeocode + "&searchtext=" + address }
 
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return b.forGeocode + fmt.Sprintf("&maxresults=%d&searchtext=", limit) + address
+}
+
+func (b baseURL) LanguageParams(language string) url.Values {
+	return url.Values{"language": {language}}
+}
+
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 	return b.forReverseGeocode + fmt.Sprintf("&prox=%f,%f,%d", l.Lat, l.Lng, r)
 }
@@ -93,6 +110,50 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 	}, nil
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	if len(r.Response.View) == 0 {
+		return nil, nil
+	}
+	res := r.Response.View[0].Result
+	results := make([]geo.Result, 0, len(res))
+	for _, v := range res {
+		p := v.Location.DisplayPosition
+		result := geo.Result{
+			Location:         geo.Location{Lat: p.Latitude, Lng: p.Longitude},
+			FormattedAddress: v.Location.Address.Label,
+			PlaceType:        v.MatchLevel,
+			Precision:        matchLevelPrecision(v.MatchLevel),
+			Confidence:       v.Relevance,
+		}
+		if m := v.Location.MapView; m.TopLeft.Latitude != 0 || m.BottomRight.Latitude != 0 {
+			result.BoundingBox = &geo.BoundingBox{
+				South: m.BottomRight.Latitude,
+				West:  m.TopLeft.Longitude,
+				North: m.TopLeft.Latitude,
+				East:  m.BottomRight.Longitude,
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func matchLevelPrecision(matchLevel string) geo.Precision {
+	switch matchLevel {
+	case "houseNumber":
+		return geo.PrecisionRooftop
+	case "street", "intersection":
+		return geo.PrecisionStreet
+	case "postalCode", "district", "city":
+		return geo.PrecisionCity
+	case "county", "state":
+		return geo.PrecisionRegion
+	case "country":
+		return geo.PrecisionCountry
+	}
+	return geo.PrecisionUnknown
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
 	if len(r.Response.View) == 0 || len(r.Response.View[0].Result) == 0 {
 		return nil, nil
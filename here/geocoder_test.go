@@ -1,6 +1,8 @@
 package here_test
 
 import (
+	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -44,6 +46,37 @@ func TestReverseGeocodeWithNoResult(t *testing.T) {
 	assert.Nil(t, addr)
 }
 
+func TestGeocodeBatch(t *testing.T) {
+	var input string
+	polls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodPost:
+			b, _ := io.ReadAll(req.Body)
+			input = string(b)
+			resp.Write([]byte(jobAccepted))
+		case req.URL.Query().Get("action") == "status":
+			polls++
+			resp.Write([]byte(jobCompleted))
+		case strings.HasSuffix(req.URL.Path, "/E2Bs6sb9DPsIZkYhgEkNBRADtTcDNO0m/result"):
+			resp.Write([]byte(jobResult))
+		default:
+			resp.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	geocoder := here.BatchGeocoder(appID, appCode, ts.URL+"/jobs")
+	results, err := geocoder.GeocodeBatch(context.Background(), []string{"60 Collins St; Melbourne VIC 3000", "nowhere"})
+	assert.NoError(t, err)
+	assert.Equal(t, "recId;searchText\n1;60 Collins St, Melbourne VIC 3000\n2;nowhere\n", input)
+	assert.Equal(t, 1, polls)
+	assert.Len(t, results, 2)
+	assert.Equal(t, geo.Location{Lat: -37.81375, Lng: 144.97176}, results[0].Result.Location)
+	assert.Equal(t, geo.PrecisionRooftop, results[0].Result.Precision)
+	assert.ErrorIs(t, results[1].Err, geo.ErrNotFound)
+}
+
 func testServer(response string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		resp.Write([]byte(response))
@@ -51,6 +84,11 @@ func testServer(response string) *httptest.Server {
 }
 
 const (
+	jobAccepted  = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><ns2:SearchBatch xmlns:ns2="http://www.navteq.com/lbsp/Search-Batch/1"><Response><MetaInfo><RequestId>E2Bs6sb9DPsIZkYhgEkNBRADtTcDNO0m</RequestId></MetaInfo><Status>accepted</Status><TotalCount>0</TotalCount><ValidCount>0</ValidCount><InvalidCount>0</InvalidCount><ProcessedCount>0</ProcessedCount><PendingCount>0</PendingCount><SuccessCount>0</SuccessCount><ErrorCount>0</ErrorCount></Response></ns2:SearchBatch>`
+	jobCompleted = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><ns2:SearchBatch xmlns:ns2="http://www.navteq.com/lbsp/Search-Batch/1"><Response><MetaInfo><RequestId>E2Bs6sb9DPsIZkYhgEkNBRADtTcDNO0m</RequestId></MetaInfo><Status>completed</Status><TotalCount>2</TotalCount><ValidCount>2</ValidCount><InvalidCount>0</InvalidCount><ProcessedCount>2</ProcessedCount><PendingCount>0</PendingCount><SuccessCount>1</SuccessCount><ErrorCount>1</ErrorCount></Response></ns2:SearchBatch>`
+	jobResult    = "recId;SeqNumber;seqLength;displayLatitude;displayLongitude;locationLabel;relevance;matchLevel\n" +
+		"1;1;1;-37.81375;144.97176;60 Collins St, Melbourne VIC 3000, Australia;1.0;houseNumber\n" +
+		"2;1;0;;;;;\n"
 	response1 = `{
    "Response":{
       "MetaInfo":{
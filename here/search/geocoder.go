@@ -4,14 +4,32 @@ package search
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/codingsince1985/geo-golang"
 )
 
 type (
-	baseURL         struct{ forGeocode, forReverseGeocode string }
+	baseURL         struct{ forGeocode, forReverseGeocode, forSuggest, forDiscover, forBrowse string }
 	geocodeResponse struct {
 		Items []struct {
+			ID                     string
+			Title                  string
+			ResultType             string
+			HouseNumberType        string
+			AdministrativeAreaType string
+			Scoring                struct {
+				QueryScore float64
+			}
+			// Distance and Categories are set for places
+			Distance   float64
+			Categories []struct {
+				Name    string
+				Primary bool
+			}
+			MapView struct {
+				West, South, East, North float64
+			}
 			Address struct {
 				Label       string
 				CountryCode string
@@ -39,8 +57,12 @@ func Geocoder(apiKey string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder: baseURL{
 			getGeocodeURL(p, baseURLs...),
-			getReverseGeocodeURL(p, baseURLs...)},
+			getReverseGeocodeURL(p, baseURLs...),
+			getSuggestURL(p, baseURLs...),
+			getDiscoverURL(p, baseURLs...),
+			getBrowseURL(p, baseURLs...)},
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "here/search",
 	}
 }
 
@@ -58,7 +80,112 @@ func getReverseGeocodeURL(p string, baseURLs ...string) string {
 	return "https://revgeocode.search.hereapi.com/v1/revgeocode?" + p
 }
 
-func (b baseURL) GeocodeURL(address string) string { return b.forGeocode + "&limit=1&q=" + address }
+func getSuggestURL(p string, baseURLs ...string) string {
+	if len(baseURLs) > 0 {
+		return baseURLs[0]
+	}
+	return "https://autosuggest.search.hereapi.com/v1/autosuggest?" + p
+}
+
+func getDiscoverURL(p string, baseURLs ...string) string {
+	if len(baseURLs) > 0 {
+		return baseURLs[0]
+	}
+	return "https://discover.search.hereapi.com/v1/discover?" + p
+}
+
+func getBrowseURL(p string, baseURLs ...string) string {
+	if len(baseURLs) > 0 {
+		return baseURLs[0]
+	}
+	return "https://browse.search.hereapi.com/v1/browse?" + p
+}
+
+func (b baseURL) GeocodeURL(address string) string { return b.GeocodeAllURL(address, 1) }
+
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return b.forGeocode + fmt.Sprintf("&limit=%d&q=", limit) + address
+}
+
+// StructuredGeocodeURL builds a qualified query, one qq sub-parameter per address field
+func (b baseURL) StructuredGeocodeURL(a geo.Address) string {
+	country := a.Country
+	if country == "" {
+		country = a.CountryCode
+	}
+
+	var qq []string
+	for _, f := range []struct{ name, value string }{
+		{"houseNumber", a.HouseNumber},
+		{"street", a.Street},
+		{"district", a.Suburb},
+		{"city", a.City},
+		{"county", a.County},
+		{"state", a.State},
+		{"postalCode", a.Postcode},
+		{"country", country},
+	} {
+		if f.value != "" {
+			qq = append(qq, f.name+"="+f.value)
+		}
+	}
+	return b.forGeocode + "&limit=1&qq=" + url.QueryEscape(strings.Join(qq, ";"))
+}
+
+// SuggestURL uses the Autosuggest endpoint, which needs a position to search around:
+// pass opts.Proximity or configure the geocoder WithProximity
+func (b baseURL) SuggestURL(partial string, opts geo.SuggestOptions) string {
+	u := b.forSuggest + fmt.Sprintf("&limit=%d&q=", opts.Limit) + partial
+	if p := opts.Proximity; p != nil {
+		u += fmt.Sprintf("&at=%f,%f", p.Lat, p.Lng)
+	}
+	return u
+}
+
+// PlaceSearchURL discovers places by name or kind, or browses every place around q.Near if there is no text.
+// Both endpoints need a position, q.Near, searched as a circle of q.Radius: HERE takes either at or in=circle, not both.
+func (b baseURL) PlaceSearchURL(text string, q geo.PlaceQuery) string {
+	var u string
+	if text == "" {
+		u = b.forBrowse + fmt.Sprintf("&limit=%d", q.Limit)
+	} else {
+		u = b.forDiscover + fmt.Sprintf("&limit=%d&q=", q.Limit) + text
+	}
+	if n := q.Near; n != nil {
+		circle := fmt.Sprintf("circle:%f,%f;r=%.0f", n.Lat, n.Lng, q.Radius)
+		u += "&in=" + url.QueryEscape(circle)
+	}
+	return u
+}
+
+func (b baseURL) LanguageParams(language string) url.Values {
+	return url.Values{"lang": {language}}
+}
+
+// FilterURL restricts to countries through in=countryCode and biases toward a proximity through at.
+// The geocode endpoint does not take bounds.
+func (b baseURL) FilterURL(rawURL string, f geo.Filter) (string, error) {
+	if f.Bounds != nil {
+		return "", geo.UnsupportedError("bounds")
+	}
+
+	params := url.Values{}
+	if len(f.Countries) > 0 {
+		codes := make([]string, len(f.Countries))
+		for i, c := range f.Countries {
+			code, ok := alpha3[strings.ToUpper(c)]
+			if !ok {
+				return "", fmt.Errorf("%w: unknown country code %q", geo.ErrInvalidRequest, c)
+			}
+			codes[i] = code
+		}
+		params.Set("in", "countryCode:"+strings.Join(codes, ","))
+	}
+	if p := f.Proximity; p != nil {
+		params.Set("at", fmt.Sprintf("%f,%f", p.Lat, p.Lng))
+	}
+	return geo.SetParams(rawURL, params), nil
+}
 
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 	return b.forReverseGeocode + fmt.Sprintf("&limit=1&at=%f,%f", l.Lat, l.Lng)
@@ -75,6 +202,79 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 	}, nil
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	results := make([]geo.Result, 0, len(r.Items))
+	for _, item := range r.Items {
+		result := geo.Result{
+			Location:         geo.Location{Lat: item.Position.Lat, Lng: item.Position.Lng},
+			FormattedAddress: item.Address.Label,
+			PlaceType:        item.ResultType,
+			Confidence:       item.Scoring.QueryScore,
+		}
+		switch item.ResultType {
+		case "houseNumber", "place":
+			result.Precision = geo.PrecisionRooftop
+			if item.HouseNumberType == "interpolated" {
+				result.Precision = geo.PrecisionStreet
+			}
+		case "street", "intersection", "addressBlock":
+			result.Precision = geo.PrecisionStreet
+		case "locality", "postalCodePoint":
+			result.Precision = geo.PrecisionCity
+		case "administrativeArea":
+			result.Precision = geo.PrecisionRegion
+			if item.AdministrativeAreaType == "country" {
+				result.Precision = geo.PrecisionCountry
+			}
+		}
+		if m := item.MapView; m.North != 0 || m.South != 0 {
+			result.BoundingBox = &geo.BoundingBox{South: m.South, West: m.West, North: m.North, East: m.East}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Suggestions skips query suggestions, which have no position, as they complete a search term rather than an address
+func (r *geocodeResponse) Suggestions() ([]geo.Suggestion, error) {
+	results, err := r.Candidates()
+	if err != nil {
+		return nil, err
+	}
+	var suggestions []geo.Suggestion
+	for i, item := range r.Items {
+		if item.Position.Lat == 0 && item.Position.Lng == 0 {
+			continue
+		}
+		text := item.Title
+		if text == "" {
+			text = item.Address.Label
+		}
+		suggestions = append(suggestions, geo.Suggestion{ID: item.ID, Text: text, Result: &results[i]})
+	}
+	return suggestions, nil
+}
+
+func (r *geocodeResponse) PlaceResults() ([]geo.Place, error) {
+	places := make([]geo.Place, 0, len(r.Items))
+	for _, item := range r.Items {
+		place := geo.Place{
+			ID:       item.ID,
+			Name:     item.Title,
+			Location: geo.Location{Lat: item.Position.Lat, Lng: item.Position.Lng},
+			Address:  item.Address.Label,
+			Distance: item.Distance,
+		}
+		for _, c := range item.Categories {
+			if place.Category == "" || c.Primary {
+				place.Category = c.Name
+			}
+		}
+		places = append(places, place)
+	}
+	return places, nil
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
 	if len(r.Items) == 0 {
 		return nil, nil
@@ -1,8 +1,10 @@
 package search_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
@@ -47,6 +49,55 @@ func TestReverseGeocodeWithNoResult(t *testing.T) {
 	assert.Nil(t, addr)
 }
 
+func TestSuggest(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		resp.Write([]byte(responseAutosuggest))
+	}))
+	defer ts.Close()
+
+	geocoder := search.Geocoder(apiKey, ts.URL+"/?").(geo.Suggester)
+	suggestions, err := geocoder.Suggest(context.Background(), "60 Collins", geo.SuggestOptions{Proximity: &geo.Location{Lat: -37.8, Lng: 144.9}})
+	require.NoError(t, err)
+	assert.Equal(t, "60 Collins", query.Get("q"))
+	assert.Equal(t, "-37.800000,144.900000", query.Get("at"))
+	assert.Equal(t, "5", query.Get("limit"))
+	require.Len(t, suggestions, 1)
+	assert.Equal(t, "here:af:streetsection:bzJ7hv3U4lYMr2N8bKc7sB:CgcIBCCi8ZYCEAEaAjYw", suggestions[0].ID)
+	assert.Equal(t, "60 Collins St, Melbourne VIC 3000, Australia", suggestions[0].Text)
+	require.NotNil(t, suggestions[0].Result)
+	assert.Equal(t, geo.Location{Lat: -37.81375, Lng: 144.97176}, suggestions[0].Result.Location)
+	assert.Equal(t, geo.PrecisionRooftop, suggestions[0].Result.Precision)
+}
+
+func TestSearchPlaces(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		resp.Write([]byte(responseDiscover))
+	}))
+	defer ts.Close()
+
+	geocoder := search.Geocoder(apiKey, ts.URL+"/?").(geo.PlaceSearcher)
+	places, err := geocoder.SearchPlaces(context.Background(), geo.PlaceQuery{
+		Text: "coffee",
+		Near: &geo.Location{Lat: -37.8136, Lng: 144.9631},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "coffee", query.Get("q"))
+	assert.Equal(t, "circle:-37.813600,144.963100;r=5000", query.Get("in"))
+	assert.False(t, query.Has("at"))
+	assert.Equal(t, []geo.Place{{
+		ID:       "here:pds:place:036r1zcj-9ac2a4c4b0d34b6e8b8f1c3b1b9b7e2a",
+		Name:     "Brother Baba Budan",
+		Category: "Coffee Shop",
+		Location: geo.Location{Lat: -37.8133, Lng: 144.961},
+		Address:  "Brother Baba Budan, 359 Little Bourke St, Melbourne VIC 3000, Australia",
+		Distance: 189,
+	}}, places)
+}
+
 func testServer(response string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		resp.Write([]byte(response))
@@ -54,6 +105,49 @@ func testServer(response string) *httptest.Server {
 }
 
 const (
+	responseDiscover = `{
+  "items": [
+    {
+      "title": "Brother Baba Budan",
+      "id": "here:pds:place:036r1zcj-9ac2a4c4b0d34b6e8b8f1c3b1b9b7e2a",
+      "resultType": "place",
+      "address": {
+        "label": "Brother Baba Budan, 359 Little Bourke St, Melbourne VIC 3000, Australia"
+      },
+      "position": {
+        "lat": -37.8133,
+        "lng": 144.961
+      },
+      "distance": 189,
+      "categories": [
+        {"id": "100-1000-0000", "name": "Restaurant"},
+        {"id": "100-1100-0010", "name": "Coffee Shop", "primary": true}
+      ]
+    }
+  ]
+}`
+	responseAutosuggest = `{
+  "items": [
+    {
+      "title": "60 Collins St, Melbourne VIC 3000, Australia",
+      "id": "here:af:streetsection:bzJ7hv3U4lYMr2N8bKc7sB:CgcIBCCi8ZYCEAEaAjYw",
+      "resultType": "houseNumber",
+      "houseNumberType": "PA",
+      "address": {
+        "label": "60 Collins St, Melbourne VIC 3000, Australia"
+      },
+      "position": {
+        "lat": -37.81375,
+        "lng": 144.97176
+      }
+    },
+    {
+      "title": "collins street",
+      "id": "here:cm:chainquery:collins-street",
+      "resultType": "chainQuery"
+    }
+  ]
+}`
 	response1 = `{
   "items": [
     {
@@ -1,6 +1,7 @@
 package geo
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"encoding/xml"
@@ -16,6 +17,9 @@ import (
 // DefaultTimeout for the request execution
 const DefaultTimeout = time.Second * 8
 
+// DefaultLimit is the number of candidates GeocodeAll asks for when given a non-positive limit
+const DefaultLimit = 10
+
 // ErrTimeout occurs when no response returned within timeoutInSeconds
 var ErrTimeout = errors.New("TIMEOUT")
 
@@ -25,6 +29,24 @@ type EndpointBuilder interface {
 	ReverseGeocodeURL(Location) string
 }
 
+// MultiEndpointBuilder is implemented by EndpointBuilders whose provider
+// can be asked for more than one geocoding candidate
+type MultiEndpointBuilder interface {
+	GeocodeAllURL(address string, limit int) string
+}
+
+// StructuredEndpointBuilder is implemented by EndpointBuilders whose provider
+// accepts the fields of an address as separate query parameters
+type StructuredEndpointBuilder interface {
+	StructuredGeocodeURL(address Address) string
+}
+
+// LanguageEndpointBuilder is implemented by EndpointBuilders whose provider can answer in a requested language.
+// LanguageParams returns the query parameters asking for language, or nil if the provider does not offer it.
+type LanguageEndpointBuilder interface {
+	LanguageParams(language string) url.Values
+}
+
 // ResponseParserFactory creates a new ResponseParser
 type ResponseParserFactory func() ResponseParser
 
@@ -34,95 +56,213 @@ type ResponseParser interface {
 	Address() (*Address, error)
 }
 
-// HTTPGeocoder has EndpointBuilder and ResponseParser
+// MultiResponseParser is implemented by ResponseParsers that can return
+// every candidate of a geocode response, best match first
+type MultiResponseParser interface {
+	Candidates() ([]Result, error)
+}
+
+// HTTPGeocoder has EndpointBuilder and ResponseParser.
+// Provider names the service in the errors it returns.
 type HTTPGeocoder struct {
 	EndpointBuilder
 	ResponseParserFactory
 	ResponseUnmarshaler
+	Provider string
+	Options  Options
 }
 
-func (g HTTPGeocoder) geocodeWithContext(ctx context.Context, address string) (*Location, error) {
-	responseParser := g.ResponseParserFactory()
-	var responseUnmarshaler ResponseUnmarshaler = &JSONUnmarshaler{}
-	if g.ResponseUnmarshaler != nil {
-		responseUnmarshaler = g.ResponseUnmarshaler
+// WithOptions returns a copy of g with opts applied
+func (g HTTPGeocoder) WithOptions(opts ...Option) Geocoder {
+	g.Options = g.Options.Apply(opts...)
+	return g
+}
+
+// Geocode returns location for address
+func (g HTTPGeocoder) Geocode(address string) (*Location, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	return g.GeocodeContext(ctx, address)
+}
+
+// GeocodeContext returns location for address, aborting the request when ctx is done.
+// ErrNotFound is returned when the provider has no result.
+func (g HTTPGeocoder) GeocodeContext(ctx context.Context, address string) (*Location, error) {
+	return g.location(ctx, g.GeocodeURL(url.QueryEscape(address)))
+}
+
+// GeocodeAll returns up to limit candidates for address, best match first
+func (g HTTPGeocoder) GeocodeAll(address string, limit int) ([]Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	return g.GeocodeAllContext(ctx, address, limit)
+}
+
+// GeocodeAllContext returns up to limit candidates for address, aborting the request when ctx is done.
+// Providers that only ever return one candidate yield a slice of at most one result.
+func (g HTTPGeocoder) GeocodeAllContext(ctx context.Context, address string, limit int) ([]Result, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
 	}
 
-	type geoResp struct {
-		l *Location
-		e error
+	var u string
+	if b, ok := g.EndpointBuilder.(MultiEndpointBuilder); ok {
+		u = b.GeocodeAllURL(url.QueryEscape(address), limit)
+	} else {
+		u = g.GeocodeURL(url.QueryEscape(address))
 	}
-	ch := make(chan geoResp, 1)
 
-	go func(ch chan geoResp) {
-		if err := response(ctx, g.GeocodeURL(url.QueryEscape(address)), responseUnmarshaler, responseParser); err != nil {
-			ch <- geoResp{
-				l: nil,
-				e: err,
-			}
-		}
+	u, err := g.filter(u)
+	if err != nil {
+		return nil, err
+	}
+	responseParser := g.ResponseParserFactory()
+	if err := g.response(ctx, u, responseParser); err != nil {
+		return nil, err
+	}
 
-		loc, err := responseParser.Location()
-		ch <- geoResp{
-			l: loc,
-			e: err,
-		}
-	}(ch)
+	results, err := candidates(responseParser)
+	if err != nil {
+		return nil, NamedError(g.Provider, err)
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
 
-	select {
-	case <-ctx.Done():
-		return nil, ErrTimeout
-	case res := <-ch:
-		return res.l, res.e
+// candidates returns every result p parsed, failing with ErrNotFound if there is none
+func candidates(p ResponseParser) ([]Result, error) {
+	var results []Result
+	var err error
+	if mp, ok := p.(MultiResponseParser); ok {
+		results, err = mp.Candidates()
+	} else if loc, lerr := p.Location(); loc != nil {
+		results, err = []Result{{Location: *loc}}, lerr
+	} else {
+		err = lerr
 	}
+	if err == nil && len(results) == 0 {
+		err = ErrNotFound
+	}
+	return results, err
 }
 
-// Geocode returns location for address
-func (g HTTPGeocoder) Geocode(address string) (*Location, error) {
-	ctx, cancel := context.WithTimeout(context.TODO(), DefaultTimeout)
+// GeocodeAddress returns location for a structured address
+func (g HTTPGeocoder) GeocodeAddress(address Address) (*Location, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
 	defer cancel()
 
-	return g.geocodeWithContext(ctx, address)
+	return g.GeocodeAddressContext(ctx, address)
+}
+
+// GeocodeAddressContext returns location for a structured address, aborting the request when ctx is done.
+// Providers without a structured query are sent the single line form of the address.
+func (g HTTPGeocoder) GeocodeAddressContext(ctx context.Context, address Address) (*Location, error) {
+	b, ok := g.EndpointBuilder.(StructuredEndpointBuilder)
+	if !ok {
+		return g.GeocodeContext(ctx, address.SingleLine())
+	}
+	return g.location(ctx, b.StructuredGeocodeURL(address))
 }
 
 // ReverseGeocode returns address for location
 func (g HTTPGeocoder) ReverseGeocode(lat, lng float64) (*Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+
+	return g.ReverseGeocodeContext(ctx, lat, lng)
+}
+
+// ReverseGeocodeContext returns address for location, aborting the request when ctx is done.
+// ErrNotFound is returned when the provider has no result.
+func (g HTTPGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*Address, error) {
 	responseParser := g.ResponseParserFactory()
-	var responseUnmarshaler ResponseUnmarshaler = &JSONUnmarshaler{}
-	if g.ResponseUnmarshaler != nil {
-		responseUnmarshaler = g.ResponseUnmarshaler
+	if err := g.response(ctx, g.ReverseGeocodeURL(Location{lat, lng}), responseParser); err != nil {
+		return nil, err
 	}
 
-	ctx, cancel := context.WithTimeout(context.TODO(), DefaultTimeout)
-	defer cancel()
+	addr, err := responseParser.Address()
+	if err == nil && addr == nil {
+		err = ErrNotFound
+	}
+	if err != nil {
+		return nil, NamedError(g.Provider, err)
+	}
+	return addr, nil
+}
+
+// filter applies the Filter of g.Options to the URL of a forward geocoding request
+func (g HTTPGeocoder) filter(url string) (string, error) {
+	return g.filterWith(url, g.Options.Filter)
+}
 
-	type revResp struct {
-		a *Address
-		e error
+func (g HTTPGeocoder) filterWith(url string, f Filter) (string, error) {
+	if f.IsZero() {
+		return url, nil
 	}
-	ch := make(chan revResp, 1)
+	b, ok := g.EndpointBuilder.(FilterEndpointBuilder)
+	if !ok {
+		return "", NamedError(g.Provider, UnsupportedError("search filters"))
+	}
+	url, err := b.FilterURL(url, f)
+	if err != nil {
+		return "", NamedError(g.Provider, err)
+	}
+	return url, nil
+}
 
-	go func(ch chan revResp) {
-		if err := response(ctx, g.ReverseGeocodeURL(Location{lat, lng}), responseUnmarshaler, responseParser); err != nil {
-			ch <- revResp{
-				a: nil,
-				e: err,
-			}
-		}
+func (g HTTPGeocoder) location(ctx context.Context, url string) (*Location, error) {
+	url, err := g.filter(url)
+	if err != nil {
+		return nil, err
+	}
+	responseParser := g.ResponseParserFactory()
+	if err := g.response(ctx, url, responseParser); err != nil {
+		return nil, err
+	}
 
-		addr, err := responseParser.Address()
-		ch <- revResp{
-			a: addr,
-			e: err,
+	loc, err := responseParser.Location()
+	if err == nil && loc == nil {
+		err = ErrNotFound
+	}
+	if err != nil {
+		return nil, NamedError(g.Provider, err)
+	}
+	return loc, nil
+}
+
+func (g HTTPGeocoder) response(ctx context.Context, url string, obj ResponseParser) error {
+	return g.send(ctx, Request{Method: http.MethodGet, URL: url}, obj)
+}
+
+func (g HTTPGeocoder) send(ctx context.Context, r Request, obj ResponseParser) error {
+	if b, ok := g.EndpointBuilder.(LanguageEndpointBuilder); ok && g.Options.Language != "" {
+		r.URL = SetParams(r.URL, b.LanguageParams(g.Options.Language))
+	}
+
+	var responseUnmarshaler ResponseUnmarshaler = &JSONUnmarshaler{}
+	if g.ResponseUnmarshaler != nil {
+		responseUnmarshaler = g.ResponseUnmarshaler
+	}
+
+	if err := response(ctx, g.Options, g.Provider, r, responseUnmarshaler, obj); err != nil {
+		if ctx.Err() != nil {
+			return ContextError(ctx)
 		}
-	}(ch)
+		return NamedError(g.Provider, err)
+	}
+	return nil
+}
 
-	select {
-	case <-ctx.Done():
-		return nil, ErrTimeout
-	case res := <-ch:
-		return res.a, res.e
+// ContextError returns the reason ctx is done, reporting an exceeded deadline as ErrTimeout
+func ContextError(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ErrTimeout
 	}
+	return ctx.Err()
 }
 
 type ResponseUnmarshaler interface {
@@ -131,11 +271,16 @@ type ResponseUnmarshaler interface {
 
 type JSONUnmarshaler struct{}
 
+// Unmarshal strips the brackets of a top level array so that a single result can be decoded into a struct.
+// Values implementing json.Unmarshaler are given the body untouched, so they can decode every element of an array.
 func (*JSONUnmarshaler) Unmarshal(data []byte, v any) error {
 	body := strings.Trim(string(data), " []")
 	if body == "" {
 		return nil
 	}
+	if _, ok := v.(json.Unmarshaler); ok {
+		return json.Unmarshal(data, v)
+	}
 	return json.Unmarshal([]byte(body), v)
 }
 
@@ -145,31 +290,38 @@ func (*XMLUnmarshaler) Unmarshal(data []byte, v any) error {
 	return xml.Unmarshal(data, v)
 }
 
-// Response gets response from url
-func response(ctx context.Context, url string, unmarshaler ResponseUnmarshaler, obj ResponseParser) error {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// Response sends r and unmarshals its response into obj
+func response(ctx context.Context, opts Options, provider string, r Request, unmarshaler ResponseUnmarshaler, obj ResponseParser) error {
+	var body io.Reader
+	if r.Body != nil {
+		body = bytes.NewReader(r.Body)
+	}
+	req, err := opts.NewRequest(ctx, r.Method, r.URL, body)
 	if err != nil {
 		return err
 	}
-	req = req.WithContext(ctx)
-
-	req.Header.Add("User-Agent", "geo-golang/1.0")
+	if r.ContentType != "" {
+		req.Header.Set("Content-Type", r.ContentType)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := opts.Do(req)
 	if err != nil {
 		return err
 	}
 
 	defer resp.Body.Close()
-	data, err := io.ReadAll(resp.Body)
+	data, err := opts.ReadBody(resp)
+	if statusErr := ResponseError(resp, data); statusErr != nil {
+		return opts.Fail(resp.Request, NamedError(provider, statusErr))
+	}
 	if err != nil {
-		return err
+		return opts.Fail(resp.Request, NamedError(provider, err))
 	}
 
 	DebugLogger.Printf("Received response: %s\n", string(data))
 	if err := unmarshaler.Unmarshal(data, obj); err != nil {
 		ErrLogger.Printf("Error unmarshalling response: %s\n", err.Error())
-		return err
+		return opts.Fail(resp.Request, err)
 	}
 
 	return nil
@@ -0,0 +1,148 @@
+package geo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Interceptor hooks into every request a geocoder sends. Nil hooks are skipped.
+type Interceptor struct {
+	// BeforeRequest may modify or replace req, e.g. to sign it. Returning an error aborts the request.
+	BeforeRequest func(req *http.Request) (*http.Request, error)
+	// AfterResponse sees every response before its body is read. Returning an error fails the request.
+	AfterResponse func(req *http.Request, resp *http.Response) error
+	// OnError sees every error of a request, including those returned by other hooks and provider errors
+	OnError func(req *http.Request, err error)
+}
+
+// WithInterceptors adds interceptors to the chain. BeforeRequest hooks run in the order given,
+// AfterResponse and OnError hooks in reverse order, so the first interceptor wraps all the others.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(o *Options) {
+		o.Interceptors = append(o.Interceptors[:len(o.Interceptors):len(o.Interceptors)], interceptors...)
+	}
+}
+
+// Do sends req through the interceptor chain with the client of o.
+// Requests that get no response fail with ErrProviderUnavailable unless their context is done.
+func (o Options) Do(req *http.Request) (*http.Response, error) {
+	for _, i := range o.Interceptors {
+		if i.BeforeRequest == nil {
+			continue
+		}
+		r, err := i.BeforeRequest(req)
+		if err != nil {
+			return nil, o.Fail(req, err)
+		}
+		req = r
+	}
+
+	resp, err := o.HTTPClient().Do(req)
+	if err != nil {
+		if req.Context().Err() == nil {
+			err = fmt.Errorf("%w: %w", ErrProviderUnavailable, err)
+		}
+		return nil, o.Fail(req, err)
+	}
+
+	for n := len(o.Interceptors) - 1; n >= 0; n-- {
+		if after := o.Interceptors[n].AfterResponse; after != nil {
+			if err := after(req, resp); err != nil {
+				resp.Body.Close()
+				return nil, o.Fail(req, err)
+			}
+		}
+	}
+	return resp, nil
+}
+
+// Fail hands err to the OnError hooks and returns it, for errors found after Do returned a response
+func (o Options) Fail(req *http.Request, err error) error {
+	for n := len(o.Interceptors) - 1; n >= 0; n-- {
+		if onError := o.Interceptors[n].OnError; onError != nil {
+			onError(req, err)
+		}
+	}
+	return err
+}
+
+// HeaderInterceptor sets header on every request, replacing values of the same keys
+func HeaderInterceptor(header http.Header) Interceptor {
+	return Interceptor{
+		BeforeRequest: func(req *http.Request) (*http.Request, error) {
+			for k, v := range header {
+				req.Header[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+			}
+			return req, nil
+		},
+	}
+}
+
+// LoggingInterceptor logs every request with its status or error.
+// Query parameters that look like credentials are redacted.
+func LoggingInterceptor(logger *log.Logger) Interceptor {
+	return Interceptor{
+		AfterResponse: func(req *http.Request, resp *http.Response) error {
+			logger.Printf("%s %s: %s\n", req.Method, redactURL(req.URL), resp.Status)
+			return nil
+		},
+		OnError: func(req *http.Request, err error) {
+			logger.Printf("%s %s: %s\n", req.Method, redactURL(req.URL), err)
+		},
+	}
+}
+
+type timingKey struct{}
+
+type timing struct {
+	start    time.Time
+	recorded bool
+}
+
+// TimingInterceptor calls record once per request with the time it took until its response arrived,
+// or until it failed without one, in which case err says why
+func TimingInterceptor(record func(req *http.Request, elapsed time.Duration, err error)) Interceptor {
+	finish := func(req *http.Request, err error) {
+		t, ok := req.Context().Value(timingKey{}).(*timing)
+		if !ok || t.recorded {
+			return
+		}
+		t.recorded = true
+		record(req, time.Since(t.start), err)
+	}
+	return Interceptor{
+		BeforeRequest: func(req *http.Request) (*http.Request, error) {
+			return req.WithContext(context.WithValue(req.Context(), timingKey{}, &timing{start: time.Now()})), nil
+		},
+		AfterResponse: func(req *http.Request, resp *http.Response) error {
+			finish(req, nil)
+			return nil
+		},
+		OnError: func(req *http.Request, err error) {
+			finish(req, err)
+		},
+	}
+}
+
+// credentialParams are the lower case names of query parameters providers take credentials in
+var credentialParams = map[string]bool{
+	"key": true, "apikey": true, "api_key": true, "token": true, "access_token": true,
+	"ak": true, "sk": true, "app_code": true, "client_secret": true, "signature": true,
+}
+
+func redactURL(u *url.URL) string {
+	q := u.Query()
+	for name := range q {
+		if credentialParams[strings.ToLower(name)] {
+			q.Set(name, "REDACTED")
+		}
+	}
+	r := *u
+	r.RawQuery = q.Encode()
+	return r.Redacted()
+}
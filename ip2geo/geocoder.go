@@ -4,17 +4,18 @@ package ip2geo
 import (
 	"context"
 	"encoding/json"
-	"errors"
-	"io"
 	"net/http"
 	"net/url"
 
 	"github.com/codingsince1985/geo-golang"
 )
 
+const provider = "ip2geo"
+
 type geocoder struct {
 	apiKey  string
 	baseURL string
+	options geo.Options
 }
 
 type apiResponse struct {
@@ -68,63 +69,136 @@ func Geocoder(apiKey string, baseURLs ...string) geo.Geocoder {
 	return &geocoder{apiKey: apiKey, baseURL: baseURL}
 }
 
+// WithOptions returns a copy of the geocoder with opts applied
+func (g *geocoder) WithOptions(opts ...geo.Option) geo.Geocoder {
+	c := *g
+	c.options = g.options.Apply(opts...)
+	return &c
+}
+
 // Geocode returns location for the given IP address
 func (g *geocoder) Geocode(address string) (*geo.Location, error) {
-	resp, err := g.fetch(address)
-	if err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), geo.DefaultTimeout)
+	defer cancel()
+
+	return g.GeocodeContext(ctx, address)
+}
+
+// GeocodeContext returns location for the given IP address, aborting the request when ctx is done
+func (g *geocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	results, err := g.GeocodeAllContext(ctx, address, 1)
+	if len(results) == 0 {
 		return nil, err
 	}
+	return &results[0].Location, nil
+}
+
+// GeocodeAll returns the location of the given IP address as a single result.
+func (g *geocoder) GeocodeAll(address string, limit int) ([]geo.Result, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), geo.DefaultTimeout)
+	defer cancel()
 
-	if !resp.Success {
-		return nil, errors.New("ip2geo: " + resp.Message)
+	return g.GeocodeAllContext(ctx, address, limit)
+}
+
+// GeocodeAllContext returns the location of the given IP address as a single result,
+// aborting the request when ctx is done.
+// An IP address only ever resolves to a city, so results are never more precise than that.
+// Search filters can't narrow down an IP address, so they fail with ErrUnsupported.
+func (g *geocoder) GeocodeAllContext(ctx context.Context, address string, limit int) ([]geo.Result, error) {
+	if !g.options.Filter.IsZero() {
+		return nil, providerError("", geo.UnsupportedError("filter"))
+	}
+	resp, err := g.fetch(ctx, address)
+	if err != nil {
+		return nil, err
 	}
 
-	city := resp.Data.Continent.Country.City
+	country := resp.Data.Continent.Country
+	city := country.City
 	if city.Latitude == 0 && city.Longitude == 0 {
-		return nil, nil
+		return nil, geo.NamedError(provider, geo.ErrNotFound)
 	}
 
-	return &geo.Location{
-		Lat: city.Latitude,
-		Lng: city.Longitude,
-	}, nil
+	result := geo.Result{
+		Location: geo.Location{
+			Lat: city.Latitude,
+			Lng: city.Longitude,
+		},
+		Precision: geo.PrecisionCountry,
+	}
+	switch {
+	case city.Name != "":
+		result.FormattedAddress = city.Name + ", " + country.Name
+		result.Precision = geo.PrecisionCity
+	case country.Subdivision.Name != "":
+		result.FormattedAddress = country.Subdivision.Name + ", " + country.Name
+		result.Precision = geo.PrecisionRegion
+	default:
+		result.FormattedAddress = country.Name
+	}
+	return []geo.Result{result}, nil
 }
 
 // ReverseGeocode returns address for location.
 // ip2geo is an IP geolocation service and does not support reverse geocoding.
 func (g *geocoder) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
-	return nil, errors.New("ip2geo: reverse geocoding is not supported")
+	return g.ReverseGeocodeContext(context.Background(), lat, lng)
 }
 
-func (g *geocoder) fetch(ip string) (*apiResponse, error) {
-	reqURL := g.baseURL + "/convert?ip=" + url.QueryEscape(ip)
+// ReverseGeocodeContext returns address for location.
+// ip2geo is an IP geolocation service and does not support reverse geocoding.
+func (g *geocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	return nil, providerError("", geo.UnsupportedError("reverse geocoding"))
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), geo.DefaultTimeout)
-	defer cancel()
+func (g *geocoder) fetch(ctx context.Context, ip string) (*apiResponse, error) {
+	reqURL := g.baseURL + "/convert?ip=" + url.QueryEscape(ip)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	req, err := g.options.NewRequest(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("X-Api-Key", g.apiKey)
-	req.Header.Set("User-Agent", "geo-golang/1.0")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := g.options.Do(req)
 	if err != nil {
-		return nil, err
+		if ctx.Err() != nil {
+			return nil, geo.ContextError(ctx)
+		}
+		return nil, geo.NamedError(provider, err)
 	}
 	defer resp.Body.Close()
 
-	data, err := io.ReadAll(resp.Body)
+	data, err := g.options.ReadBody(resp)
 	if err != nil {
-		return nil, err
+		return nil, g.options.Fail(resp.Request, geo.NamedError(provider, err))
 	}
 
+	// error responses carry their code and message in the body as well, prefer those when present
 	var result apiResponse
 	if err := json.Unmarshal(data, &result); err != nil {
-		return nil, err
+		if statusErr := geo.ResponseError(resp, data); statusErr != nil {
+			err = geo.NamedError(provider, statusErr)
+		}
+		return nil, g.options.Fail(resp.Request, err)
+	}
+	if result.Code == 0 {
+		result.Code = resp.StatusCode
+	}
+	if !result.Success {
+		err := geo.HTTPStatusError(result.Code)
+		if err == nil {
+			err = geo.ErrProviderUnavailable
+		}
+		meta := geo.ParseResponseMeta(resp)
+		return nil, g.options.Fail(resp.Request, &geo.ProviderError{Provider: provider, Status: result.Message, Err: err, Response: &meta})
 	}
 
 	return &result, nil
 }
+
+func providerError(status string, err error) error {
+	return &geo.ProviderError{Provider: provider, Status: status, Err: err}
+}
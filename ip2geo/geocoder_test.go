@@ -1,11 +1,14 @@
 package ip2geo_test
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/codingsince1985/geo-golang"
 	"github.com/codingsince1985/geo-golang/ip2geo"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,20 +37,63 @@ func TestGeocodeNoCity(t *testing.T) {
 	assert.InDelta(t, -97.822, location.Lng, 0.01)
 }
 
+func TestGeocodeAll(t *testing.T) {
+	ts := testServer(response1)
+	defer ts.Close()
+
+	geocoder := ip2geo.Geocoder("test-key", ts.URL).(geo.MultiGeocoder)
+	results, err := geocoder.GeocodeAll("134.201.250.155", 5)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Los Angeles, United States", results[0].FormattedAddress)
+	assert.Equal(t, geo.PrecisionCity, results[0].Precision)
+}
+
 func TestGeocodeError(t *testing.T) {
 	ts := testServer(responseError)
 	defer ts.Close()
 
 	geocoder := ip2geo.Geocoder("bad-key", ts.URL)
 	location, err := geocoder.Geocode("8.8.8.8")
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, geo.ErrUnauthorized)
+	assert.Contains(t, err.Error(), "Invalid API key")
+	assert.Nil(t, location)
+}
+
+func TestGeocodeRateLimited(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"success":false,"code":429,"message":"Too many requests"}`))
+	}))
+	defer ts.Close()
+
+	var failed error
+	geocoder := geo.Configure(ip2geo.Geocoder("test-key", ts.URL), geo.WithInterceptors(geo.Interceptor{
+		OnError: func(_ *http.Request, err error) { failed = err },
+	}))
+	_, err := geocoder.Geocode("8.8.8.8")
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+	var pe *geo.ProviderError
+	assert.ErrorAs(t, err, &pe)
+	assert.Equal(t, 30*time.Second, pe.RetryAfter())
+	assert.Equal(t, err, failed)
+}
+
+func TestGeocodeWithFilter(t *testing.T) {
+	ts := testServer(response1)
+	defer ts.Close()
+
+	geocoder := geo.Configure(ip2geo.Geocoder("test-key", ts.URL), geo.WithCountries("us"))
+	location, err := geocoder.Geocode("134.201.250.155")
+	assert.ErrorIs(t, err, geo.ErrUnsupported)
 	assert.Nil(t, location)
 }
 
 func TestReverseGeocode(t *testing.T) {
 	geocoder := ip2geo.Geocoder("test-key")
 	address, err := geocoder.ReverseGeocode(34.0, -118.0)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, geo.ErrUnsupported)
 	assert.Nil(t, address)
 }
 
@@ -64,6 +110,35 @@ func TestGeocodeVerifiesHeader(t *testing.T) {
 	assert.Equal(t, "my-secret-key", receivedKey)
 }
 
+func TestGeocodeWithUserAgent(t *testing.T) {
+	var userAgent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	geocoder := geo.Configure(ip2geo.Geocoder("test-key", ts.URL), geo.WithUserAgent("my-app/2.0"))
+	_, err := geocoder.Geocode("134.201.250.155")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-app/2.0", userAgent)
+}
+
+func TestGeocodeContextDeadline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	geocoder := ip2geo.Geocoder("test-key", ts.URL).(geo.ContextGeocoder)
+	location, err := geocoder.GeocodeContext(ctx, "8.8.8.8")
+	assert.Equal(t, geo.ErrTimeout, err)
+	assert.Nil(t, location)
+}
+
 func testServer(response string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
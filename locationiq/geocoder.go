@@ -3,6 +3,7 @@ package locationiq
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/codingsince1985/geo-golang"
@@ -11,11 +12,7 @@ import (
 
 type baseURL string
 
-type geocodeResponse struct {
-	DisplayName     string `json:"display_name"`
-	Lat, Lon, Error string
-	Addr            osm.Address `json:"address"`
-}
+type geocodeResponse struct{ osm.Response }
 
 const (
 	defaultURL  = "http://locationiq.org/v1/"
@@ -49,11 +46,32 @@ func Geocoder(k string, z int, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(url),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "locationiq",
 	}
 }
 
-func (b baseURL) GeocodeURL(address string) string {
-	return string(b) + "search.php?key=" + key + "&format=json&limit=1&q=" + address
+func (b baseURL) GeocodeURL(address string) string { return b.GeocodeAllURL(address, 1) }
+
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return string(b) + "search.php?key=" + key + fmt.Sprintf("&format=json&limit=%d&q=", limit) + address
+}
+
+func (b baseURL) StructuredGeocodeURL(a geo.Address) string {
+	return string(b) + "search.php?key=" + key + "&format=json&limit=1&" + osm.StructuredQuery(a)
+}
+
+func (b baseURL) PlaceSearchURL(text string, q geo.PlaceQuery) string {
+	return string(b) + "search.php?key=" + key + "&format=json&" + osm.PlaceSearchQuery(text, q)
+}
+
+func (b baseURL) LanguageParams(language string) url.Values { return osm.LanguageParams(language) }
+
+func (b baseURL) FilterURL(rawURL string, f geo.Filter) (string, error) {
+	params, err := osm.FilterParams(f)
+	if err != nil {
+		return "", err
+	}
+	return geo.SetParams(rawURL, params), nil
 }
 
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
@@ -61,35 +79,39 @@ func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 }
 
 func (r *geocodeResponse) Location() (*geo.Location, error) {
-	if r.Error != "" {
-		return nil, fmt.Errorf("geocoding error: %s", r.Error)
-	}
-
+	results, err := r.Candidates()
 	// no result
-	if r.Lat == "" || r.Lon == "" {
-		return nil, nil
+	if len(results) == 0 {
+		return nil, err
 	}
+	return &results[0].Location, nil
+}
 
-	return &geo.Location{
-		Lat: geo.ParseFloat(r.Lat),
-		Lng: geo.ParseFloat(r.Lon),
-	}, nil
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	return r.Response.Results(), nil
 }
 
 func (r *geocodeResponse) Address() (*geo.Address, error) {
-	if r.Error != "" {
-		return nil, fmt.Errorf("reverse geocoding error: %s", r.Error)
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if len(r.Places) == 0 {
+		return nil, nil
 	}
 
+	p := r.Places[0]
 	return &geo.Address{
-		FormattedAddress: r.DisplayName,
-		Street:           r.Addr.Street(),
-		HouseNumber:      r.Addr.HouseNumber,
-		City:             r.Addr.Locality(),
-		Postcode:         r.Addr.Postcode,
-		Suburb:           r.Addr.Suburb,
-		State:            r.Addr.State,
-		Country:          r.Addr.Country,
-		CountryCode:      strings.ToUpper(r.Addr.CountryCode),
+		FormattedAddress: p.DisplayName,
+		Street:           p.Address.Street(),
+		HouseNumber:      p.Address.HouseNumber,
+		City:             p.Address.Locality(),
+		Postcode:         p.Address.Postcode,
+		Suburb:           p.Address.Suburb,
+		State:            p.Address.State,
+		Country:          p.Address.Country,
+		CountryCode:      strings.ToUpper(p.Address.CountryCode),
 	}, nil
 }
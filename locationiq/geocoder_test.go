@@ -1,10 +1,13 @@
 package locationiq
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"github.com/codingsince1985/geo-golang"
 )
 
 func TestGeocodeYieldsResult(t *testing.T) {
@@ -41,8 +44,8 @@ func TestGeocodeYieldsNoResult(t *testing.T) {
 		t.Errorf("Expected nil, got %#v", l)
 	}
 
-	if err != nil {
-		t.Errorf("Expected nil error, got %v", err)
+	if !errors.Is(err, geo.ErrNotFound) {
+		t.Errorf("Expected geo.ErrNotFound, got %v", err)
 	}
 }
 
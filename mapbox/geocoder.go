@@ -2,19 +2,28 @@
 package mapbox
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/codingsince1985/geo-golang"
 )
 
 type (
-	baseURL         string
+	baseURL string
+	// permanentURL is the URL of the permanent endpoint, the only one taking batches
+	permanentURL    struct{ baseURL }
 	geocodeResponse struct {
 		Features []struct {
-			PlaceName string `json:"place_name"`
+			ID        string   `json:"id"`
+			PlaceName string   `json:"place_name"`
+			PlaceType []string `json:"place_type"`
+			Relevance float64  `json:"relevance"`
 			Center    [2]float64
+			BBox      []float64       `json:"bbox"`    // west, south, east, north
 			Text      string          `json:"text"`    // usually street name
 			Address   json.RawMessage `json:"address"` // potentially house number
 			Context   []struct {
@@ -25,6 +34,9 @@ type (
 			}
 		}
 		Message string `json:"message"`
+
+		// Batch holds the response to each query of a batch request
+		Batch []geocodeResponse `json:"-"`
 	}
 )
 
@@ -33,6 +45,8 @@ const (
 	mapboxPrefixPostcode = "postcode"
 	mapboxPrefixState    = "region"
 	mapboxPrefixCountry  = "country"
+	// batchSize is the most queries a batch request may carry
+	batchSize = 50
 )
 
 // Geocoder constructs Mapbox geocoder
@@ -40,6 +54,22 @@ func Geocoder(token string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getURL(token, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "mapbox",
+	}
+}
+
+// PermanentGeocoder constructs a Mapbox geocoder for the mapbox.places-permanent endpoint, whose results may be stored
+// and which geocodes up to 50 addresses a request in GeocodeBatch. It is billed apart from the mapbox.places endpoint
+// of Geocoder, whose batches are sent one address at a time, and the token must be allowed to use it.
+func PermanentGeocoder(token string, baseURLs ...string) geo.Geocoder {
+	u := "https://api.mapbox.com/geocoding/v5/mapbox.places-permanent/*.json?access_token=" + token
+	if len(baseURLs) > 0 {
+		u = baseURLs[0]
+	}
+	return geo.HTTPGeocoder{
+		EndpointBuilder:       permanentURL{baseURL(u)},
+		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "mapbox",
 	}
 }
 
@@ -47,22 +77,70 @@ func getURL(token string, baseURLs ...string) string {
 	if len(baseURLs) > 0 {
 		return baseURLs[0]
 	}
-	return "https://api.mapbox.com/geocoding/v5/mapbox.places/*.json?limit=1&access_token=" + token
+	return "https://api.mapbox.com/geocoding/v5/mapbox.places/*.json?access_token=" + token
+}
+
+func (b baseURL) GeocodeURL(address string) string { return b.GeocodeAllURL(address, 1) }
+
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return strings.Replace(string(b), "*", address, 1) + fmt.Sprintf("&limit=%d", limit)
+}
+
+// SuggestURL asks the geocoding endpoint to treat address as the beginning of a word
+func (b baseURL) SuggestURL(partial string, opts geo.SuggestOptions) string {
+	u := b.GeocodeAllURL(partial, opts.Limit) + "&autocomplete=true"
+	if p := opts.Proximity; p != nil {
+		u += fmt.Sprintf("&proximity=%f,%f", p.Lng, p.Lat)
+	}
+	return u
 }
 
-func (b baseURL) GeocodeURL(address string) string {
-	return strings.Replace(string(b), "*", address, 1)
+func (b permanentURL) BatchSize() int { return batchSize }
+
+// BatchRequest joins addresses with semicolons, which only the permanent endpoint accepts
+func (b permanentURL) BatchRequest(addresses []string) geo.Request {
+	queries := make([]string, len(addresses))
+	for i, a := range addresses {
+		queries[i] = url.QueryEscape(a)
+	}
+	return geo.Request{
+		Method: http.MethodGet,
+		URL:    strings.Replace(string(b.baseURL), "*", strings.Join(queries, ";"), 1) + "&limit=1",
+	}
+}
+
+func (b baseURL) LanguageParams(language string) url.Values {
+	return url.Values{"language": {language}}
+}
+
+// FilterURL maps f onto country, bbox and proximity. Mapbox can only restrict to bounds, not bias toward them.
+func (b baseURL) FilterURL(rawURL string, f geo.Filter) (string, error) {
+	if f.Bounds != nil && !f.RestrictToBounds {
+		return "", geo.UnsupportedError("bounds bias")
+	}
+
+	params := url.Values{}
+	if len(f.Countries) > 0 {
+		params.Set("country", strings.ToLower(strings.Join(f.Countries, ",")))
+	}
+	if bb := f.Bounds; bb != nil {
+		params.Set("bbox", fmt.Sprintf("%f,%f,%f,%f", bb.West, bb.South, bb.East, bb.North))
+	}
+	if p := f.Proximity; p != nil {
+		params.Set("proximity", fmt.Sprintf("%f,%f", p.Lng, p.Lat))
+	}
+	return geo.SetParams(rawURL, params), nil
 }
 
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
-	return strings.Replace(string(b), "*", fmt.Sprintf("%+f,%+f", l.Lng, l.Lat), 1)
+	return strings.Replace(string(b), "*", fmt.Sprintf("%+f,%+f", l.Lng, l.Lat), 1) + "&limit=1"
 }
 
 func (r *geocodeResponse) Location() (*geo.Location, error) {
 	if len(r.Features) == 0 {
 		// error in response
 		if r.Message != "" {
-			return nil, fmt.Errorf("reverse geocoding error: %s", r.Message)
+			return nil, geo.StatusError(r.Message, geo.ErrInvalidRequest)
 		}
 		// no results
 		return nil, nil
@@ -75,11 +153,96 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 	}, nil
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	if len(r.Features) == 0 && r.Message != "" {
+		return nil, geo.StatusError(r.Message, geo.ErrInvalidRequest)
+	}
+	results := make([]geo.Result, 0, len(r.Features))
+	for _, f := range r.Features {
+		result := geo.Result{
+			Location:         geo.Location{Lat: f.Center[1], Lng: f.Center[0]},
+			FormattedAddress: f.PlaceName,
+			Confidence:       f.Relevance,
+		}
+		if len(f.PlaceType) > 0 {
+			result.PlaceType = f.PlaceType[0]
+			result.Precision = mapboxPrecision(f.PlaceType[0], len(f.Address) > 0)
+		}
+		if b := f.BBox; len(b) == 4 {
+			result.BoundingBox = &geo.BoundingBox{South: b[1], West: b[0], North: b[3], East: b[2]}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (r *geocodeResponse) Suggestions() ([]geo.Suggestion, error) {
+	results, err := r.Candidates()
+	if err != nil {
+		return nil, err
+	}
+	suggestions := make([]geo.Suggestion, len(results))
+	for i := range results {
+		suggestions[i] = geo.Suggestion{ID: r.Features[i].ID, Text: results[i].FormattedAddress, Result: &results[i]}
+	}
+	return suggestions, nil
+}
+
+// UnmarshalJSON decodes both a single response and the array of responses to a batch request
+func (r *geocodeResponse) UnmarshalJSON(data []byte) error {
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &r.Batch)
+	}
+	type response geocodeResponse
+	return json.Unmarshal(data, (*response)(r))
+}
+
+// BatchResults reads a batch of one query, which Mapbox answers like a single request, from r itself
+func (r *geocodeResponse) BatchResults(n int) ([]geo.BatchResult, error) {
+	batch := r.Batch
+	if len(batch) == 0 && n == 1 {
+		batch = []geocodeResponse{*r}
+	}
+
+	results := make([]geo.BatchResult, len(batch))
+	for i := range batch {
+		candidates, err := batch[i].Candidates()
+		if err == nil && len(candidates) == 0 {
+			err = geo.ErrNotFound
+		}
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Result = &candidates[0]
+	}
+	return results, nil
+}
+
+func mapboxPrecision(placeType string, hasHouseNumber bool) geo.Precision {
+	switch placeType {
+	case "address":
+		if hasHouseNumber {
+			return geo.PrecisionRooftop
+		}
+		return geo.PrecisionStreet
+	case "poi":
+		return geo.PrecisionRooftop
+	case mapboxPrefixPostcode, mapboxPrefixLocality, "locality", "neighborhood":
+		return geo.PrecisionCity
+	case mapboxPrefixState, "district":
+		return geo.PrecisionRegion
+	case mapboxPrefixCountry:
+		return geo.PrecisionCountry
+	}
+	return geo.PrecisionUnknown
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
 	if len(r.Features) == 0 {
 		// error in response
 		if r.Message != "" {
-			return nil, fmt.Errorf("reverse geocoding error: %s", r.Message)
+			return nil, geo.StatusError(r.Message, geo.ErrInvalidRequest)
 		}
 		// no results
 		return nil, nil
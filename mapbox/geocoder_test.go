@@ -1,10 +1,13 @@
 package mapbox_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/codingsince1985/geo-golang"
@@ -24,6 +27,81 @@ func TestGeocode(t *testing.T) {
 	assert.Equal(t, geo.Location{Lat: -37.813754, Lng: 144.971756}, *location)
 }
 
+func TestSuggest(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		resp.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	geocoder := mapbox.Geocoder(token, ts.URL+"/*.json?access_token="+token).(geo.Suggester)
+	suggestions, err := geocoder.Suggest(context.Background(), "60 Collins", geo.SuggestOptions{Limit: 1, Proximity: &geo.Location{Lat: -37.8, Lng: 144.9}})
+	assert.NoError(t, err)
+	assert.Equal(t, "true", query.Get("autocomplete"))
+	assert.Equal(t, "144.900000,-37.800000", query.Get("proximity"))
+	assert.Len(t, suggestions, 1)
+	assert.Equal(t, "address.10543198797834830", suggestions[0].ID)
+	assert.Equal(t, geo.Location{Lat: -37.813754, Lng: 144.971756}, suggestions[0].Result.Location)
+}
+
+func TestGeocodeBatch(t *testing.T) {
+	var path string
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		resp.Write([]byte("[" + response1 + "," + response3 + "]"))
+	}))
+	defer ts.Close()
+
+	geocoder := mapbox.PermanentGeocoder(token, ts.URL+"/mapbox.places-permanent/*.json?access_token="+token).(geo.BatchGeocoder)
+	results, err := geocoder.GeocodeBatch(context.Background(), []string{"60 Collins St, Melbourne VIC 3000", "nowhere"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/mapbox.places-permanent/60+Collins+St,+Melbourne+VIC+3000;nowhere.json", path)
+	assert.Len(t, results, 2)
+	assert.Equal(t, geo.Location{Lat: -37.813754, Lng: 144.971756}, results[0].Result.Location)
+	assert.ErrorIs(t, results[1].Err, geo.ErrNotFound)
+}
+
+func TestGeocodeBatchWithFilter(t *testing.T) {
+	ts := testServer("[" + response1 + "]")
+	defer ts.Close()
+
+	geocoder := geo.Configure(mapbox.PermanentGeocoder(token, ts.URL+"/*.json?access_token="+token), geo.WithCountries("AU")).(geo.BatchGeocoder)
+	results, err := geocoder.GeocodeBatch(context.Background(), []string{"60 Collins St, Melbourne VIC 3000"})
+	assert.ErrorIs(t, err, geo.ErrUnsupported)
+	assert.Nil(t, results)
+}
+
+func TestGeocodeBatchOfOne(t *testing.T) {
+	ts := testServer(response1)
+	defer ts.Close()
+
+	geocoder := mapbox.PermanentGeocoder(token, ts.URL+"/*.json?access_token="+token).(geo.BatchGeocoder)
+	results, err := geocoder.GeocodeBatch(context.Background(), []string{"60 Collins St, Melbourne VIC 3000"})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, geo.Location{Lat: -37.813754, Lng: 144.971756}, results[0].Result.Location)
+}
+
+func TestGeocodeBatchOneByOne(t *testing.T) {
+	var paths []string
+	var mu sync.Mutex
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		paths = append(paths, req.URL.Path)
+		mu.Unlock()
+		resp.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	// the endpoint Geocoder sends lookups to doesn't take batches
+	geocoder := mapbox.Geocoder(token, ts.URL+"/mapbox.places/*.json?access_token="+token).(geo.BatchGeocoder)
+	results, err := geocoder.GeocodeBatch(context.Background(), []string{"60 Collins St, Melbourne VIC 3000", "64 Elizabeth St"})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.ElementsMatch(t, []string{"/mapbox.places/60+Collins+St,+Melbourne+VIC+3000.json", "/mapbox.places/64+Elizabeth+St.json"}, paths)
+}
+
 func TestReverseGeocode(t *testing.T) {
 	ts := testServer(response2)
 	defer ts.Close()
@@ -51,7 +129,7 @@ func TestReverseGeocodeWithNoResult(t *testing.T) {
 
 	geocoder := mapbox.Geocoder(token, ts.URL+"/")
 	addr, err := geocoder.ReverseGeocode(-37.813754, 164.971756)
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
 
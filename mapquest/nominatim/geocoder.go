@@ -3,6 +3,7 @@ package nominatim
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/codingsince1985/geo-golang"
@@ -12,11 +13,7 @@ import (
 type (
 	baseURL string
 
-	geocodeResponse struct {
-		DisplayName     string `json:"display_name"`
-		Lat, Lon, Error string
-		Addr            osm.Address `json:"address"`
-	}
+	geocodeResponse struct{ osm.Response }
 )
 
 var key string
@@ -27,6 +24,7 @@ func Geocoder(k string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getURL(baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "mapquest/nominatim",
 	}
 }
 
@@ -37,8 +35,28 @@ func getURL(baseURLs ...string) string {
 	return "http://open.mapquestapi.com/nominatim/v1/"
 }
 
-func (b baseURL) GeocodeURL(address string) string {
-	return string(b) + "search.php?key=" + key + "&format=json&limit=1&q=" + address
+func (b baseURL) GeocodeURL(address string) string { return b.GeocodeAllURL(address, 1) }
+
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return string(b) + "search.php?key=" + key + fmt.Sprintf("&format=json&limit=%d&q=", limit) + address
+}
+
+func (b baseURL) StructuredGeocodeURL(a geo.Address) string {
+	return string(b) + "search.php?key=" + key + "&format=json&limit=1&" + osm.StructuredQuery(a)
+}
+
+func (b baseURL) PlaceSearchURL(text string, q geo.PlaceQuery) string {
+	return string(b) + "search.php?key=" + key + "&format=json&" + osm.PlaceSearchQuery(text, q)
+}
+
+func (b baseURL) LanguageParams(language string) url.Values { return osm.LanguageParams(language) }
+
+func (b baseURL) FilterURL(rawURL string, f geo.Filter) (string, error) {
+	params, err := osm.FilterParams(f)
+	if err != nil {
+		return "", err
+	}
+	return geo.SetParams(rawURL, params), nil
 }
 
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
@@ -46,31 +64,39 @@ func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 }
 
 func (r *geocodeResponse) Location() (*geo.Location, error) {
-	if r.Error != "" {
-		return nil, fmt.Errorf("geocode error: %s", r.Error)
+	results, err := r.Candidates()
+	if len(results) == 0 {
+		return nil, err
 	}
+	return &results[0].Location, nil
+}
 
-	return &geo.Location{
-		Lat: geo.ParseFloat(r.Lat),
-		Lng: geo.ParseFloat(r.Lon),
-	}, nil
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	return r.Response.Results(), nil
 }
 
 func (r *geocodeResponse) Address() (*geo.Address, error) {
-	if r.Error != "" {
-		return nil, fmt.Errorf("reverse geocode error: %s", r.Error)
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if len(r.Places) == 0 {
+		return nil, nil
 	}
 
+	p := r.Places[0]
 	return &geo.Address{
-		FormattedAddress: r.DisplayName,
-		HouseNumber:      r.Addr.HouseNumber,
-		Street:           r.Addr.Street(),
-		Suburb:           r.Addr.Suburb,
-		City:             r.Addr.Locality(),
-		State:            r.Addr.State,
-		County:           r.Addr.County,
-		Postcode:         r.Addr.Postcode,
-		Country:          r.Addr.Country,
-		CountryCode:      strings.ToUpper(r.Addr.CountryCode),
+		FormattedAddress: p.DisplayName,
+		HouseNumber:      p.Address.HouseNumber,
+		Street:           p.Address.Street(),
+		Suburb:           p.Address.Suburb,
+		City:             p.Address.Locality(),
+		State:            p.Address.State,
+		County:           p.Address.County,
+		Postcode:         p.Address.Postcode,
+		Country:          p.Address.Country,
+		CountryCode:      strings.ToUpper(p.Address.CountryCode),
 	}, nil
 }
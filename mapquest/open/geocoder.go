@@ -17,15 +17,20 @@ type (
 					Lat float64
 					Lng float64
 				}
-				PostalCode string
-				Street     string
-				AdminArea6 string // neighbourhood
-				AdminArea5 string // city
-				AdminArea4 string // county
-				AdminArea3 string // state
-				AdminArea1 string // country (ISO 3166-1 alpha-2 code)
+				PostalCode     string
+				Street         string
+				GeocodeQuality string
+				AdminArea6     string // neighbourhood
+				AdminArea5     string // city
+				AdminArea4     string // county
+				AdminArea3     string // state
+				AdminArea1     string // country (ISO 3166-1 alpha-2 code)
 			}
 		}
+		Info struct {
+			StatusCode int
+			Messages   []string
+		}
 	}
 )
 
@@ -35,6 +40,7 @@ func Geocoder(key string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getURL(key, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "mapquest/open",
 	}
 }
 
@@ -49,11 +55,30 @@ func (b baseURL) GeocodeURL(address string) string {
 	return strings.Replace(string(b), "*", "address", 1) + address
 }
 
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return b.GeocodeURL(address) + fmt.Sprintf("&maxResults=%d", limit)
+}
+
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 	return strings.Replace(string(b), "*", "reverse", 1) + fmt.Sprintf("%f,%f", l.Lat, l.Lng)
 }
 
+// err maps the status code of a failed request onto a geo error, 0 means success
+func (r *geocodeResponse) err() error {
+	if r.Info.StatusCode == 0 {
+		return nil
+	}
+	err := geo.HTTPStatusError(r.Info.StatusCode)
+	if err == nil {
+		err = geo.ErrProviderUnavailable
+	}
+	return geo.StatusError(strings.Join(r.Info.Messages, " "), err)
+}
+
 func (r *geocodeResponse) Location() (*geo.Location, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
 	if len(r.Results) == 0 || len(r.Results[0].Locations) == 0 {
 		return nil, nil
 	}
@@ -65,7 +90,51 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 	}, nil
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
+	if len(r.Results) == 0 {
+		return nil, nil
+	}
+	results := make([]geo.Result, 0, len(r.Results[0].Locations))
+	for _, l := range r.Results[0].Locations {
+		var parts []string
+		for _, p := range []string{l.Street, l.PostalCode, l.AdminArea5, l.AdminArea3, l.AdminArea1} {
+			if p != "" {
+				parts = append(parts, p)
+			}
+		}
+		results = append(results, geo.Result{
+			Location:         geo.Location{Lat: l.LatLng.Lat, Lng: l.LatLng.Lng},
+			FormattedAddress: strings.Join(parts, ", "),
+			PlaceType:        l.GeocodeQuality,
+			Precision:        qualityPrecision(l.GeocodeQuality),
+		})
+	}
+	return results, nil
+}
+
+func qualityPrecision(quality string) geo.Precision {
+	switch quality {
+	case "POINT", "ADDRESS":
+		return geo.PrecisionRooftop
+	case "INTERSECTION", "STREET":
+		return geo.PrecisionStreet
+	case "NEIGHBORHOOD", "CITY", "ZIP", "ZIP_EXTENDED":
+		return geo.PrecisionCity
+	case "COUNTY", "STATE":
+		return geo.PrecisionRegion
+	case "COUNTRY":
+		return geo.PrecisionCountry
+	}
+	return geo.PrecisionUnknown
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
+	if err := r.err(); err != nil {
+		return nil, err
+	}
 	if len(r.Results) == 0 || len(r.Results[0].Locations) == 0 {
 		return nil, nil
 	}
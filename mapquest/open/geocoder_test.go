@@ -41,7 +41,7 @@ func TestReverseGeocodeWithNoResult(t *testing.T) {
 	geocoder := open.Geocoder(key, ts.URL+"/")
 	//geocoder := open.Geocoder(key)
 	addr, err := geocoder.ReverseGeocode(-37.813743, 164.971745)
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
 
@@ -3,6 +3,7 @@ package mapzen
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	geo "github.com/codingsince1985/geo-golang"
@@ -21,7 +22,10 @@ type (
 			Geometry struct {
 				Coordinates []float64
 			}
+			BBox       []float64 // west, south, east, north
 			Properties struct {
+				Layer       string
+				Confidence  float64
 				Name        string
 				HouseNumber string
 				Street      string
@@ -42,6 +46,7 @@ func Geocoder(key string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getUrl(key, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "mapzen",
 	}
 }
 
@@ -52,11 +57,17 @@ func getUrl(key string, baseURLs ...string) string {
 	return "https://search.mapzen.com/v1/*" + "&api_key=" + key
 }
 
-func (b baseURL) GeocodeURL(address string) string {
-	params := fmt.Sprintf("search?size=%d&text=%s", 1, address)
+func (b baseURL) GeocodeURL(address string) string { return b.GeocodeAllURL(address, 1) }
+
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	params := fmt.Sprintf("search?size=%d&text=%s", limit, address)
 	return strings.Replace(string(b), "*", params, 1)
 }
 
+func (b baseURL) LanguageParams(language string) url.Values {
+	return url.Values{"lang": {language}}
+}
+
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 	params := fmt.Sprintf("reverse?size=%d&point.lat=%f&point.lon=%f", 1, l.Lat, l.Lng)
 	return strings.Replace(string(b), "*", params, 1)
@@ -75,6 +86,44 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 	return &geo.Location{Lat: pt[1], Lng: pt[0]}, nil
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	var results []geo.Result
+	for _, f := range r.Features {
+		pt := f.Geometry.Coordinates
+		if len(pt) < 2 {
+			continue
+		}
+		result := geo.Result{
+			Location:         geo.Location{Lat: pt[1], Lng: pt[0]},
+			FormattedAddress: f.Properties.Label,
+			PlaceType:        f.Properties.Layer,
+			Precision:        layerPrecision(f.Properties.Layer),
+			Confidence:       f.Properties.Confidence,
+		}
+		if b := f.BBox; len(b) == 4 {
+			result.BoundingBox = &geo.BoundingBox{South: b[1], West: b[0], North: b[3], East: b[2]}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func layerPrecision(layer string) geo.Precision {
+	switch layer {
+	case "address", "venue":
+		return geo.PrecisionRooftop
+	case "street":
+		return geo.PrecisionStreet
+	case "neighbourhood", "borough", "locality", "localadmin", "postalcode":
+		return geo.PrecisionCity
+	case "county", "macrocounty", "region", "macroregion":
+		return geo.PrecisionRegion
+	case "country":
+		return geo.PrecisionCountry
+	}
+	return geo.PrecisionUnknown
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
 	if len(r.Features) == 0 {
 		return nil, nil
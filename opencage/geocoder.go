@@ -3,6 +3,7 @@ package opencage
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/codingsince1985/geo-golang"
@@ -16,7 +17,14 @@ type (
 		Results []struct {
 			Formatted  string
 			Geometry   geo.Location
-			Components osm.Address
+			Components struct {
+				osm.Address
+				Type string `json:"_type"`
+			}
+			Confidence int // 1 to 10, from the size of the bounds
+			Bounds     struct {
+				Northeast, Southwest geo.Location
+			}
 		}
 		Status struct {
 			Code    int
@@ -30,6 +38,7 @@ func Geocoder(key string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getURL(key, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "opencage",
 	}
 }
 
@@ -42,13 +51,21 @@ func getURL(key string, baseURLs ...string) string {
 
 func (b baseURL) GeocodeURL(address string) string { return string(b) + address }
 
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return string(b) + address + fmt.Sprintf("&limit=%d", limit)
+}
+
+func (b baseURL) LanguageParams(language string) url.Values {
+	return url.Values{"language": {language}}
+}
+
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 	return string(b) + fmt.Sprintf("%+f,%+f", l.Lat, l.Lng)
 }
 
 func (r *geocodeResponse) Location() (*geo.Location, error) {
 	if r.Status.Code >= 400 {
-		return nil, fmt.Errorf("geocoding error: %s", r.Status.Message)
+		return nil, geo.StatusError(r.Status.Message, geo.HTTPStatusError(r.Status.Code))
 	}
 	if len(r.Results) == 0 {
 		return nil, nil
@@ -60,9 +77,51 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 	}, nil
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	if r.Status.Code >= 400 {
+		return nil, geo.StatusError(r.Status.Message, geo.HTTPStatusError(r.Status.Code))
+	}
+	results := make([]geo.Result, 0, len(r.Results))
+	for _, res := range r.Results {
+		result := geo.Result{
+			Location:         res.Geometry,
+			FormattedAddress: res.Formatted,
+			PlaceType:        res.Components.Type,
+			Precision:        componentPrecision(res.Components.Type),
+			Confidence:       float64(res.Confidence) / 10,
+		}
+		if b := res.Bounds; b.Northeast != (geo.Location{}) || b.Southwest != (geo.Location{}) {
+			result.BoundingBox = &geo.BoundingBox{
+				South: b.Southwest.Lat,
+				West:  b.Southwest.Lng,
+				North: b.Northeast.Lat,
+				East:  b.Northeast.Lng,
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func componentPrecision(typ string) geo.Precision {
+	switch typ {
+	case "building", "house":
+		return geo.PrecisionRooftop
+	case "road":
+		return geo.PrecisionStreet
+	case "city", "town", "village", "hamlet", "neighbourhood", "suburb", "postcode":
+		return geo.PrecisionCity
+	case "county", "state_district", "state":
+		return geo.PrecisionRegion
+	case "country":
+		return geo.PrecisionCountry
+	}
+	return geo.PrecisionUnknown
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
 	if r.Status.Code >= 400 {
-		return nil, fmt.Errorf("geocoding error: %s", r.Status.Message)
+		return nil, geo.StatusError(r.Status.Message, geo.HTTPStatusError(r.Status.Code))
 	}
 	if len(r.Results) == 0 {
 		return nil, nil
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/codingsince1985/geo-golang"
 	"github.com/codingsince1985/geo-golang/opencage"
 	"github.com/stretchr/testify/assert"
 )
@@ -28,6 +29,21 @@ func TestGeocode(t *testing.T) {
 	assert.InDelta(t, 144.9665563, location.Lng, locDelta)
 }
 
+func TestGeocodeAll(t *testing.T) {
+	ts := testServer(response1)
+	defer ts.Close()
+
+	geocoder := opencage.Geocoder(key, ts.URL+"/").(geo.MultiGeocoder)
+	results, err := geocoder.GeocodeAll("60 Collins St, Melbourne VIC 3000", 1)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Collins Street, Melbourne VIC 3000, Australia", results[0].FormattedAddress)
+	assert.Equal(t, "road", results[0].PlaceType)
+	assert.Equal(t, geo.PrecisionStreet, results[0].Precision)
+	assert.Equal(t, 1.0, results[0].Confidence)
+	assert.Equal(t, &geo.BoundingBox{South: -37.8169249, West: 144.9617036, North: -37.8162553, East: 144.9640149}, results[0].BoundingBox)
+}
+
 func TestReverseGeocode(t *testing.T) {
 	ts := testServer(response2)
 	defer ts.Close()
@@ -44,7 +60,7 @@ func TestReverseGeocodeWithNoResult(t *testing.T) {
 
 	geocoder := opencage.Geocoder(key, ts.URL+"/")
 	address, err := geocoder.ReverseGeocode(-37.8154176, 164.9665563)
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, address)
 }
 
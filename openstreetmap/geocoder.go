@@ -3,6 +3,7 @@ package openstreetmap
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/codingsince1985/geo-golang"
@@ -11,13 +12,7 @@ import (
 
 type (
 	baseURL         string
-	geocodeResponse struct {
-		DisplayName string `json:"display_name"`
-		Lat         string
-		Lon         string
-		Error       string
-		Addr        osm.Address `json:"address"`
-	}
+	geocodeResponse struct{ osm.Response }
 )
 
 // Geocoder constructs OpenStreetMap geocoder
@@ -28,11 +23,32 @@ func GeocoderWithURL(nominatimURL string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(nominatimURL),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "openstreetmap",
 	}
 }
 
-func (b baseURL) GeocodeURL(address string) string {
-	return string(b) + "search?format=json&limit=1&q=" + address
+func (b baseURL) GeocodeURL(address string) string { return b.GeocodeAllURL(address, 1) }
+
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return string(b) + fmt.Sprintf("search?format=json&limit=%d&q=", limit) + address
+}
+
+func (b baseURL) StructuredGeocodeURL(a geo.Address) string {
+	return string(b) + "search?format=json&limit=1&" + osm.StructuredQuery(a)
+}
+
+func (b baseURL) PlaceSearchURL(text string, q geo.PlaceQuery) string {
+	return string(b) + "search?format=json&" + osm.PlaceSearchQuery(text, q)
+}
+
+func (b baseURL) LanguageParams(language string) url.Values { return osm.LanguageParams(language) }
+
+func (b baseURL) FilterURL(rawURL string, f geo.Filter) (string, error) {
+	params, err := osm.FilterParams(f)
+	if err != nil {
+		return "", err
+	}
+	return geo.SetParams(rawURL, params), nil
 }
 
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
@@ -40,33 +56,38 @@ func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 }
 
 func (r *geocodeResponse) Location() (*geo.Location, error) {
-	if r.Error != "" {
-		return nil, fmt.Errorf("geocoding error: %s", r.Error)
-	}
-	if r.Lat == "" && r.Lon == "" {
-		return nil, nil
+	results, err := r.Candidates()
+	if len(results) == 0 {
+		return nil, err
 	}
+	return &results[0].Location, nil
+}
 
-	return &geo.Location{
-		Lat: geo.ParseFloat(r.Lat),
-		Lng: geo.ParseFloat(r.Lon),
-	}, nil
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	return r.Response.Results(), nil
 }
 
 func (r *geocodeResponse) Address() (*geo.Address, error) {
-	if r.Error != "" {
-		return nil, fmt.Errorf("reverse geocoding error: %s", r.Error)
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if len(r.Places) == 0 {
+		return nil, nil
 	}
 
+	p := r.Places[0]
 	return &geo.Address{
-		FormattedAddress: r.DisplayName,
-		HouseNumber:      r.Addr.HouseNumber,
-		Street:           r.Addr.Street(),
-		Postcode:         r.Addr.Postcode,
-		City:             r.Addr.Locality(),
-		Suburb:           r.Addr.Suburb,
-		State:            r.Addr.State,
-		Country:          r.Addr.Country,
-		CountryCode:      strings.ToUpper(r.Addr.CountryCode),
+		FormattedAddress: p.DisplayName,
+		HouseNumber:      p.Address.HouseNumber,
+		Street:           p.Address.Street(),
+		Postcode:         p.Address.Postcode,
+		City:             p.Address.Locality(),
+		Suburb:           p.Address.Suburb,
+		State:            p.Address.State,
+		Country:          p.Address.Country,
+		CountryCode:      strings.ToUpper(p.Address.CountryCode),
 	}, nil
 }
@@ -1,10 +1,15 @@
 package openstreetmap_test
 
 import (
+	"context"
+	"errors"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/codingsince1985/geo-golang"
 	"github.com/codingsince1985/geo-golang/openstreetmap"
@@ -21,6 +26,92 @@ func TestGeocode(t *testing.T) {
 	assert.Equal(t, geo.Location{Lat: -37.8157915, Lng: 144.9656171}, *location)
 }
 
+func TestGeocodeAddress(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		resp.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	geocoder := openstreetmap.GeocoderWithURL(ts.URL + "/").(geo.StructuredGeocoder)
+	location, err := geocoder.GeocodeAddress(geo.Address{
+		HouseNumber: "60",
+		Street:      "Collins St",
+		City:        "Melbourne",
+		State:       "VIC",
+		Postcode:    "3000",
+		CountryCode: "AU",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, geo.Location{Lat: -37.8157915, Lng: 144.9656171}, *location)
+	assert.Equal(t, "60 Collins St", query.Get("street"))
+	assert.Equal(t, "Melbourne", query.Get("city"))
+	assert.Equal(t, "3000", query.Get("postalcode"))
+	assert.Equal(t, "au", query.Get("countrycodes"))
+	assert.Empty(t, query.Get("q"))
+}
+
+func TestGeocodeAll(t *testing.T) {
+	var query string
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.RawQuery
+		resp.Write([]byte(response5))
+	}))
+	defer ts.Close()
+
+	geocoder := openstreetmap.GeocoderWithURL(ts.URL + "/").(geo.MultiGeocoder)
+	locations, err := geocoder.GeocodeAll("Springfield", 5)
+	assert.Nil(t, err)
+	assert.Contains(t, query, "limit=5")
+	assert.Len(t, locations, 2)
+	assert.Equal(t, geo.Location{Lat: 39.7990175, Lng: -89.6439575}, locations[0].Location)
+	assert.Equal(t, "Springfield, Sangamon County, Illinois, United States", locations[0].FormattedAddress)
+	assert.Equal(t, geo.PrecisionCity, locations[0].Precision)
+	assert.Equal(t, &geo.BoundingBox{South: 39.6533, North: 39.8733, West: -89.7709, East: -89.5717}, locations[0].BoundingBox)
+	assert.Equal(t, geo.Location{Lat: 37.2081729, Lng: -93.2922715}, locations[1].Location)
+
+	locations, err = geocoder.GeocodeAll("Springfield", 1)
+	assert.Nil(t, err)
+	assert.Len(t, locations, 1)
+	assert.Equal(t, geo.Location{Lat: 39.7990175, Lng: -89.6439575}, locations[0].Location)
+}
+
+func TestGeocodeAllWithNoResult(t *testing.T) {
+	ts := testServer("[]")
+	defer ts.Close()
+
+	geocoder := openstreetmap.GeocoderWithURL(ts.URL + "/").(geo.MultiGeocoder)
+	locations, err := geocoder.GeocodeAll("nowhere", 5)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
+	assert.Empty(t, locations)
+}
+
+func TestGeocodeBatch(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("q") == "Springfield" {
+			resp.Write([]byte(response5))
+			return
+		}
+		resp.Write([]byte("[]"))
+	}))
+	defer ts.Close()
+
+	geocoder := geo.AsBatchGeocoder(openstreetmap.GeocoderWithURL(ts.URL + "/"))
+	addresses := []string{"nowhere", "Springfield", "nowhere", "Springfield", "nowhere", "Springfield"}
+	results, err := geocoder.GeocodeBatch(context.Background(), addresses)
+	assert.Nil(t, err)
+	assert.Len(t, results, len(addresses))
+	for i, r := range results {
+		if addresses[i] == "nowhere" {
+			assert.ErrorIs(t, r.Err, geo.ErrNotFound)
+			continue
+		}
+		assert.Nil(t, r.Err)
+		assert.Equal(t, geo.Location{Lat: 39.7990175, Lng: -89.6439575}, r.Result.Location)
+	}
+}
+
 func TestReverseGeocode(t *testing.T) {
 	ts := testServer(response2)
 	defer ts.Close()
@@ -52,6 +143,212 @@ func TestReverseGeocodeWithBrokenResponse(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestGeocodeHTTPStatus(t *testing.T) {
+	for status, expected := range map[int]error{
+		http.StatusTooManyRequests:    geo.ErrQuotaExceeded,
+		http.StatusForbidden:          geo.ErrUnauthorized,
+		http.StatusBadRequest:         geo.ErrInvalidRequest,
+		http.StatusServiceUnavailable: geo.ErrProviderUnavailable,
+	} {
+		ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			resp.WriteHeader(status)
+		}))
+
+		geocoder := openstreetmap.GeocoderWithURL(ts.URL + "/")
+		location, err := geocoder.Geocode("60 Collins St, Melbourne VIC 3000")
+		assert.Nil(t, location)
+		assert.ErrorIs(t, err, expected)
+		assert.Contains(t, err.Error(), "openstreetmap")
+		ts.Close()
+	}
+}
+
+func TestGeocodeRateLimited(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Header().Set("Retry-After", "30")
+		resp.Header().Set("X-RateLimit-Limit", "60")
+		resp.Header().Set("X-RateLimit-Remaining", "0")
+		resp.Header().Set("X-RateLimit-Reset", "1700000000")
+		resp.WriteHeader(http.StatusTooManyRequests)
+		resp.Write([]byte("<html>\n<body>Too many requests</body>\n</html>"))
+	}))
+	defer ts.Close()
+
+	geocoder := openstreetmap.GeocoderWithURL(ts.URL + "/")
+	_, err := geocoder.Geocode("60 Collins St, Melbourne VIC 3000")
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+
+	var providerErr *geo.ProviderError
+	assert.True(t, errors.As(err, &providerErr))
+	assert.Equal(t, 30*time.Second, providerErr.RetryAfter())
+	assert.Equal(t, geo.RateLimit{Limit: 60, Remaining: 0, Reset: time.Unix(1700000000, 0)}, providerErr.Response.RateLimit)
+	assert.Equal(t, "<html> <body>Too many requests</body> </html>", providerErr.Body)
+}
+
+func TestGeocodeBodyTooLarge(t *testing.T) {
+	ts := testServer(response1)
+	defer ts.Close()
+
+	geocoder := geo.Configure(openstreetmap.GeocoderWithURL(ts.URL+"/"), geo.WithMaxBodySize(64))
+	location, err := geocoder.Geocode("60 Collins St, Melbourne VIC 3000")
+	assert.Nil(t, location)
+	assert.ErrorIs(t, err, geo.ErrProviderUnavailable)
+}
+
+func TestGeocodeWithOptions(t *testing.T) {
+	var header http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		header = req.Header
+		resp.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	geocoder := geo.Configure(openstreetmap.GeocoderWithURL(ts.URL+"/"),
+		geo.WithHTTPClient(ts.Client()),
+		geo.WithUserAgent("my-app/2.0 (ops@example.com)"),
+		geo.WithReferer("https://example.com/"),
+	)
+	location, err := geocoder.Geocode("60 Collins St, Melbourne VIC 3000")
+	assert.Nil(t, err)
+	assert.Equal(t, geo.Location{Lat: -37.8157915, Lng: 144.9656171}, *location)
+	assert.Equal(t, "my-app/2.0 (ops@example.com)", header.Get("User-Agent"))
+	assert.Equal(t, "https://example.com/", header.Get("Referer"))
+
+	_, err = openstreetmap.GeocoderWithURL(ts.URL + "/").Geocode("60 Collins St, Melbourne VIC 3000")
+	assert.Nil(t, err)
+	assert.Equal(t, geo.DefaultUserAgent, header.Get("User-Agent"))
+	assert.Empty(t, header.Get("Referer"))
+}
+
+func TestGeocodeWithFilter(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		resp.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	geocoder := geo.Configure(openstreetmap.GeocoderWithURL(ts.URL+"/"),
+		geo.WithCountries("AU", "NZ"),
+		geo.WithBoundsRestriction(geo.BoundingBox{South: -38, West: 144, North: -37, East: 145}),
+	)
+	_, err := geocoder.Geocode("60 Collins St, Melbourne VIC 3000")
+	assert.Nil(t, err)
+	assert.Equal(t, "60 Collins St, Melbourne VIC 3000", query.Get("q"))
+	assert.Equal(t, "au,nz", query.Get("countrycodes"))
+	assert.Equal(t, "144.000000,-38.000000,145.000000,-37.000000", query.Get("viewbox"))
+	assert.Equal(t, "1", query.Get("bounded"))
+
+	_, err = geo.Configure(openstreetmap.GeocoderWithURL(ts.URL+"/"), geo.WithProximity(geo.Location{Lat: -37.8, Lng: 144.9})).
+		Geocode("60 Collins St, Melbourne VIC 3000")
+	assert.ErrorIs(t, err, geo.ErrUnsupported)
+}
+
+func TestSearchPlaces(t *testing.T) {
+	var query url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query()
+		resp.Write([]byte(responsePlaces))
+	}))
+	defer ts.Close()
+
+	near := geo.Location{Lat: -37.8136, Lng: 144.9631}
+	geocoder := openstreetmap.GeocoderWithURL(ts.URL + "/").(geo.PlaceSearcher)
+	places, err := geocoder.SearchPlaces(context.Background(), geo.PlaceQuery{Text: "cafe", Near: &near, Radius: 1000, Limit: 3})
+	assert.Nil(t, err)
+	assert.Equal(t, "cafe", query.Get("amenity"))
+	assert.Equal(t, "3", query.Get("limit"))
+	assert.Equal(t, "1", query.Get("bounded"))
+	assert.Equal(t, "144.951716,-37.822593,144.974484,-37.804607", query.Get("viewbox"))
+	assert.Len(t, places, 1)
+	assert.Equal(t, "N1234567890", places[0].ID)
+	assert.Equal(t, "Brother Baba Budan", places[0].Name)
+	assert.Equal(t, "cafe", places[0].Category)
+	assert.InDelta(t, 188, places[0].Distance, 5)
+}
+
+func TestGeocodeWithInterceptors(t *testing.T) {
+	var header http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		header = req.Header
+		if req.URL.Query().Get("q") == "banned" {
+			resp.WriteHeader(http.StatusForbidden)
+			return
+		}
+		resp.Write([]byte(response1))
+	}))
+	defer ts.Close()
+
+	var calls []string
+	var timings []error
+	var logs strings.Builder
+	geocoder := geo.Configure(openstreetmap.GeocoderWithURL(ts.URL+"/"), geo.WithInterceptors(
+		geo.HeaderInterceptor(http.Header{"X-Request-Id": {"42"}}),
+		geo.LoggingInterceptor(log.New(&logs, "", 0)),
+		geo.TimingInterceptor(func(req *http.Request, elapsed time.Duration, err error) {
+			timings = append(timings, err)
+		}),
+		geo.Interceptor{
+			BeforeRequest: func(req *http.Request) (*http.Request, error) {
+				calls = append(calls, "before")
+				return req, nil
+			},
+			AfterResponse: func(req *http.Request, resp *http.Response) error {
+				calls = append(calls, "after")
+				return nil
+			},
+			OnError: func(req *http.Request, err error) {
+				calls = append(calls, "error")
+			},
+		},
+	))
+
+	_, err := geocoder.Geocode("60 Collins St, Melbourne VIC 3000")
+	assert.Nil(t, err)
+	assert.Equal(t, "42", header.Get("X-Request-Id"))
+	assert.Equal(t, []string{"before", "after"}, calls)
+	assert.Equal(t, []error{nil}, timings)
+
+	_, err = geocoder.Geocode("banned")
+	assert.ErrorIs(t, err, geo.ErrUnauthorized)
+	assert.Equal(t, []string{"before", "after", "before", "after", "error"}, calls)
+	assert.Len(t, timings, 2)
+	assert.Contains(t, logs.String(), "403 Forbidden")
+	assert.Contains(t, logs.String(), "openstreetmap: unauthorized")
+}
+
+func TestGeocodeContextCancelled(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	geocoder := openstreetmap.GeocoderWithURL(ts.URL + "/").(geo.ContextGeocoder)
+	start := time.Now()
+	location, err := geocoder.GeocodeContext(ctx, "60 Collins St, Melbourne VIC 3000")
+	assert.Nil(t, location)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), geo.DefaultTimeout)
+}
+
+func TestReverseGeocodeContextDeadline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	geocoder := openstreetmap.GeocoderWithURL(ts.URL + "/").(geo.ContextGeocoder)
+	addr, err := geocoder.ReverseGeocodeContext(ctx, -37.8157915, 144.9656171)
+	assert.Nil(t, addr)
+	assert.Equal(t, geo.ErrTimeout, err)
+}
+
 func testServer(response string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		resp.Write([]byte(response))
@@ -112,4 +409,38 @@ const (
 	response4 = `{
    broken response
 }`
+	response5 = `[
+   {
+      "place_id":"297541814",
+      "lat":"39.7990175",
+      "lon":"-89.6439575",
+      "display_name":"Springfield, Sangamon County, Illinois, United States",
+      "class":"boundary",
+      "type":"administrative",
+      "addresstype":"city",
+      "boundingbox":["39.6533","39.8733","-89.7709","-89.5717"],
+      "importance":0.72
+   },
+   {
+      "place_id":"297603457",
+      "lat":"37.2081729",
+      "lon":"-93.2922715",
+      "display_name":"Springfield, Greene County, Missouri, United States",
+      "class":"boundary",
+      "type":"administrative",
+      "importance":0.68
+   }
+]`
+	responsePlaces = `[
+  {
+    "osm_type": "node",
+    "osm_id": 1234567890,
+    "lat": "-37.8133",
+    "lon": "144.9610",
+    "class": "amenity",
+    "type": "cafe",
+    "name": "Brother Baba Budan",
+    "display_name": "Brother Baba Budan, 359, Little Bourke Street, Melbourne, Victoria, 3000, Australia"
+  }
+]`
 )
@@ -0,0 +1,121 @@
+package geo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultUserAgent identifies requests of geocoders that are not given their own User-Agent
+const DefaultUserAgent = "geo-golang/1.0"
+
+// DefaultMaxBodySize is the most bytes of a response body read when Options does not say otherwise
+const DefaultMaxBodySize = 4 << 20
+
+// Options configures how a geocoder talks to its provider
+type Options struct {
+	// Client sends the requests, http.DefaultClient if nil
+	Client *http.Client
+	// UserAgent identifies the application, DefaultUserAgent if empty.
+	// Nominatim's usage policy requires it to name your application.
+	UserAgent string
+	// Referer is sent with every request if set
+	Referer string
+	// MaxBodySize caps the bytes read from a response body, DefaultMaxBodySize if not positive
+	MaxBodySize int64
+	// Interceptors hook into every request, see WithInterceptors
+	Interceptors []Interceptor
+	// Filter narrows down forward geocoding, see WithCountries, WithBoundsBias and WithProximity
+	Filter Filter
+	// Language is the BCP 47 tag of the language results should be in, e.g. "fr" or "pt-BR"
+	Language string
+}
+
+// Option sets a field of Options
+type Option func(*Options)
+
+// WithHTTPClient sends requests with c, e.g. to use a custom transport, proxy or TLS configuration
+func WithHTTPClient(c *http.Client) Option { return func(o *Options) { o.Client = c } }
+
+// WithUserAgent sends ua as the User-Agent of every request
+func WithUserAgent(ua string) Option { return func(o *Options) { o.UserAgent = ua } }
+
+// WithReferer sends referer as the Referer of every request
+func WithReferer(referer string) Option { return func(o *Options) { o.Referer = referer } }
+
+// WithLanguage asks for results in language, given as a BCP 47 tag such as "fr" or "pt-BR".
+// Each provider maps it to its own parameter and sends it as Accept-Language too;
+// providers without a choice of language keep answering in their default one.
+func WithLanguage(language string) Option { return func(o *Options) { o.Language = language } }
+
+// WithMaxBodySize caps the bytes read from a response body at n
+func WithMaxBodySize(n int64) Option { return func(o *Options) { o.MaxBodySize = n } }
+
+// Configurable is implemented by geocoders that accept Options
+type Configurable interface {
+	WithOptions(opts ...Option) Geocoder
+}
+
+// Configure returns g with opts applied. Geocoders that are not Configurable are returned unchanged.
+func Configure(g Geocoder, opts ...Option) Geocoder {
+	if c, ok := g.(Configurable); ok {
+		return c.WithOptions(opts...)
+	}
+	return g
+}
+
+// Apply returns a copy of o with opts applied
+func (o Options) Apply(opts ...Option) Options {
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// HTTPClient returns the client requests are sent with
+func (o Options) HTTPClient() *http.Client {
+	if o.Client != nil {
+		return o.Client
+	}
+	return http.DefaultClient
+}
+
+// NewRequest creates a request carrying the User-Agent and Referer of o
+func (o Options) NewRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	ua := o.UserAgent
+	if ua == "" {
+		ua = DefaultUserAgent
+	}
+	req.Header.Set("User-Agent", ua)
+	if o.Referer != "" {
+		req.Header.Set("Referer", o.Referer)
+	}
+	if o.Language != "" {
+		req.Header.Set("Accept-Language", o.Language)
+	}
+	return req, nil
+}
+
+// ReadBody reads the body of resp, failing once it exceeds MaxBodySize.
+// The bytes read so far are returned along with that error.
+func (o Options) ReadBody(resp *http.Response) ([]byte, error) {
+	limit := o.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return data, err
+	}
+	if int64(len(data)) > limit {
+		return data[:limit], fmt.Errorf("%w: response body exceeds %d bytes", ErrProviderUnavailable, limit)
+	}
+	return data, nil
+}
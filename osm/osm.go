@@ -2,6 +2,144 @@
 // and some helper functions to reduce code repetition across specific client implementations.
 package osm
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+// Response is a Nominatim response: an array of places for a search,
+// a single place for a reverse lookup, or an error
+type Response struct {
+	Places []Place
+	Error  string
+}
+
+// Place is a single result of a Nominatim search or reverse lookup
+type Place struct {
+	OSMType     string      `json:"osm_type"`
+	OSMID       json.Number `json:"osm_id"` // a string in some Nominatim versions
+	Name        string      `json:"name"`
+	DisplayName string      `json:"display_name"`
+	Lat         string      `json:"lat"`
+	Lon         string      `json:"lon"`
+	Class       string      `json:"class"`
+	Type        string      `json:"type"`
+	AddressType string      `json:"addresstype"`
+	BoundingBox []string    `json:"boundingbox"` // south, north, west, east
+	Address     Address     `json:"address"`
+}
+
+// UnmarshalJSON decodes both the array returned by search and the object returned by reverse
+func (r *Response) UnmarshalJSON(data []byte) error {
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '[' {
+		return json.Unmarshal(data, &r.Places)
+	}
+
+	var obj struct {
+		Place
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	r.Error = obj.Error
+	if obj.Lat != "" || obj.Lon != "" || obj.DisplayName != "" {
+		r.Places = []Place{obj.Place}
+	}
+	return nil
+}
+
+// Results returns every place in the response as a geo.Result
+func (r Response) Results() []geo.Result {
+	results := make([]geo.Result, 0, len(r.Places))
+	for _, p := range r.Places {
+		results = append(results, p.Result())
+	}
+	return results
+}
+
+// PlaceResults returns every place in the response as a geo.Place
+func (r Response) PlaceResults() ([]geo.Place, error) {
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	places := make([]geo.Place, 0, len(r.Places))
+	for _, p := range r.Places {
+		places = append(places, p.Place())
+	}
+	return places, nil
+}
+
+// Place returns the place as a geo.Place, identified the way Nominatim's lookup takes it, e.g. "N240109189"
+func (p Place) Place() geo.Place {
+	var id string
+	if p.OSMType != "" {
+		id = strings.ToUpper(p.OSMType[:1]) + p.OSMID.String()
+	}
+	return geo.Place{
+		ID:       id,
+		Name:     p.Name,
+		Category: p.Type,
+		Location: p.Location(),
+		Address:  p.DisplayName,
+	}
+}
+
+// Location returns the coordinates of the place
+func (p Place) Location() geo.Location {
+	return geo.Location{
+		Lat: geo.ParseFloat(p.Lat),
+		Lng: geo.ParseFloat(p.Lon),
+	}
+}
+
+// Result returns the place as a geo.Result.
+// Nominatim doesn't score how well a place matches the query, so Confidence is left zero.
+func (p Place) Result() geo.Result {
+	result := geo.Result{
+		Location:         p.Location(),
+		FormattedAddress: p.DisplayName,
+		PlaceType:        p.Type,
+		Precision:        p.precision(),
+	}
+	if b := p.BoundingBox; len(b) == 4 {
+		result.BoundingBox = &geo.BoundingBox{
+			South: geo.ParseFloat(b[0]),
+			North: geo.ParseFloat(b[1]),
+			West:  geo.ParseFloat(b[2]),
+			East:  geo.ParseFloat(b[3]),
+		}
+	}
+	return result
+}
+
+func (p Place) precision() geo.Precision {
+	typ := p.AddressType
+	if typ == "" {
+		typ = p.Type
+	}
+	switch {
+	case p.Class == "building" || typ == "house" || typ == "building":
+		return geo.PrecisionRooftop
+	case p.Class == "highway" || typ == "road":
+		return geo.PrecisionStreet
+	}
+	switch typ {
+	case "city", "town", "village", "hamlet", "suburb", "neighbourhood", "quarter", "postcode":
+		return geo.PrecisionCity
+	case "county", "state_district", "state", "region", "province":
+		return geo.PrecisionRegion
+	case "country":
+		return geo.PrecisionCountry
+	}
+	return geo.PrecisionUnknown
+}
+
 // Address contains address fields specific to OpenStreetMap
 type Address struct {
 	HouseNumber   string `json:"house_number"`
@@ -61,3 +199,80 @@ func (a Address) Street() string {
 
 	return street
 }
+
+// Err maps the error message of a failed request onto a geo error
+func (r Response) Err() error {
+	if r.Error == "" {
+		return nil
+	}
+
+	var err error
+	switch msg := strings.ToLower(r.Error); {
+	case strings.Contains(msg, "unable to geocode"):
+		err = geo.ErrNotFound
+	case strings.Contains(msg, "rate limit"):
+		err = geo.ErrQuotaExceeded
+	case strings.Contains(msg, "key"):
+		err = geo.ErrUnauthorized
+	default:
+		err = geo.ErrInvalidRequest
+	}
+	return geo.StatusError(r.Error, err)
+}
+
+// LanguageParams asks Nominatim for results in language
+func LanguageParams(language string) url.Values {
+	return url.Values{"accept-language": {language}}
+}
+
+// FilterParams maps f onto countrycodes, viewbox and bounded.
+// Nominatim has no notion of proximity.
+func FilterParams(f geo.Filter) (url.Values, error) {
+	if f.Proximity != nil {
+		return nil, geo.UnsupportedError("proximity")
+	}
+
+	v := url.Values{}
+	if len(f.Countries) > 0 {
+		v.Set("countrycodes", strings.ToLower(strings.Join(f.Countries, ",")))
+	}
+	if b := f.Bounds; b != nil {
+		v.Set("viewbox", fmt.Sprintf("%f,%f,%f,%f", b.West, b.South, b.East, b.North))
+		if f.RestrictToBounds {
+			v.Set("bounded", "1")
+		}
+	}
+	return v, nil
+}
+
+// PlaceSearchQuery encodes q as an amenity search, bounded to the box around q.Near if it has one.
+// text is q.Text escaped for use in a query.
+func PlaceSearchQuery(text string, q geo.PlaceQuery) string {
+	query := fmt.Sprintf("limit=%d&amenity=%s", q.Limit, text)
+	if n := q.Near; n != nil {
+		b := geo.BoundsAround(*n, q.Radius)
+		query += fmt.Sprintf("&viewbox=%f,%f,%f,%f&bounded=1", b.West, b.South, b.East, b.North)
+	}
+	return query
+}
+
+// StructuredQuery encodes an address as the street, city, county, state, country and postalcode
+// parameters of a Nominatim structured search
+func StructuredQuery(a geo.Address) string {
+	v := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	set("street", strings.TrimSpace(a.HouseNumber+" "+a.Street))
+	set("city", a.City)
+	set("county", a.County)
+	set("state", a.State)
+	set("country", a.Country)
+	set("postalcode", a.Postcode)
+	if a.Country == "" {
+		set("countrycodes", strings.ToLower(a.CountryCode))
+	}
+	return v.Encode()
+}
@@ -0,0 +1,91 @@
+package geo
+
+import (
+	"context"
+	"net/url"
+)
+
+// DefaultPlaceRadius is the radius in metres searched around PlaceQuery.Near when it is given no Radius
+const DefaultPlaceRadius = 5000
+
+// Place is a named point of interest, such as a shop or a landmark
+type Place struct {
+	ID   string
+	Name string
+	// Category is the provider's own name for the kind of place, e.g. "cafe"
+	Category string
+	Location Location
+	// Address is the formatted address of the place, if the provider reports one
+	Address string
+	// Distance is how far the place is from PlaceQuery.Near in metres, or 0 if the query had no Near
+	Distance float64
+}
+
+// PlaceQuery describes the places to search for
+type PlaceQuery struct {
+	// Text names the place or its kind, e.g. "Eiffel Tower" or "coffee"
+	Text string
+	// Near is the location to search around
+	Near *Location
+	// Radius is how many metres around Near to search, DefaultPlaceRadius if not positive
+	Radius float64
+	// Limit caps the number of places, DefaultLimit if not positive
+	Limit int
+}
+
+// PlaceSearcher finds points of interest by name or kind
+type PlaceSearcher interface {
+	SearchPlaces(ctx context.Context, q PlaceQuery) ([]Place, error)
+}
+
+// PlaceEndpointBuilder is implemented by EndpointBuilders whose provider can search points of interest.
+// text is q.Text escaped for use in a query.
+type PlaceEndpointBuilder interface {
+	PlaceSearchURL(text string, q PlaceQuery) string
+}
+
+// PlaceResponseParser is implemented by ResponseParsers that can parse the response of a place search
+type PlaceResponseParser interface {
+	PlaceResults() ([]Place, error)
+}
+
+// SearchPlaces returns up to q.Limit places matching q, best match first.
+// Distances the provider does not report are measured from q.Near.
+// Finding no place is not an error.
+func (g HTTPGeocoder) SearchPlaces(ctx context.Context, q PlaceQuery) ([]Place, error) {
+	b, ok := g.EndpointBuilder.(PlaceEndpointBuilder)
+	if !ok {
+		return nil, NamedError(g.Provider, UnsupportedError("place search"))
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	if q.Radius <= 0 {
+		q.Radius = DefaultPlaceRadius
+	}
+
+	responseParser := g.ResponseParserFactory()
+	if err := g.response(ctx, b.PlaceSearchURL(url.QueryEscape(q.Text), q), responseParser); err != nil {
+		return nil, err
+	}
+	p, ok := responseParser.(PlaceResponseParser)
+	if !ok {
+		return nil, NamedError(g.Provider, UnsupportedError("place search"))
+	}
+	places, err := p.PlaceResults()
+	if err != nil {
+		return nil, NamedError(g.Provider, err)
+	}
+
+	if len(places) > q.Limit {
+		places = places[:q.Limit]
+	}
+	if q.Near != nil {
+		for i := range places {
+			if places[i].Distance == 0 {
+				places[i].Distance = Distance(*q.Near, places[i].Location)
+			}
+		}
+	}
+	return places, nil
+}
@@ -0,0 +1,184 @@
+// Package race asks several geocoders at once and keeps the first acceptable answer
+package race
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+// ErrNotAccepted is reported for geocoders whose result was turned down by the acceptance test of the race
+var ErrNotAccepted = errors.New("result not accepted")
+
+// Option configures a Racer
+type Option func(*Racer)
+
+// WithHedge staggers the requests: each geocoder is asked d after the previous one, or as soon as every
+// geocoder asked so far has failed. Without it, every geocoder is asked at once.
+func WithHedge(d time.Duration) Option { return func(r *Racer) { r.hedge = d } }
+
+// WithAccept turns down the results of address lookups for which accept returns false, e.g. to require a precision.
+// Reverse lookups accept any address found.
+func WithAccept(accept func(geo.Result) bool) Option { return func(r *Racer) { r.accept = accept } }
+
+// WithNames names the geocoders in errors and answers, in the order they were given.
+// Geocoders without a name are named after the provider of a geo.HTTPGeocoder or their position.
+func WithNames(names ...string) Option { return func(r *Racer) { r.names = names } }
+
+// Racer sends a lookup to its geocoders concurrently and answers with the first acceptable result,
+// cancelling the requests still running
+type Racer struct {
+	geocoders []geo.Geocoder
+	names     []string
+	hedge     time.Duration
+	accept    func(geo.Result) bool
+}
+
+// Geocoder races geocoders, every one of them asked at once
+func Geocoder(geocoders ...geo.Geocoder) geo.Geocoder { return New(geocoders) }
+
+// New returns a Racer of geocoders
+func New(geocoders []geo.Geocoder, opts ...Option) *Racer {
+	r := &Racer{geocoders: geocoders}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	names := make([]string, len(geocoders))
+	for i, g := range geocoders {
+		if i < len(r.names) {
+			names[i] = r.names[i]
+		}
+		names[i] = geo.MemberName(g, names[i], fmt.Sprintf("geocoder %d", i+1))
+	}
+	r.names = names
+	return r
+}
+
+// Geocode returns location for address
+func (r *Racer) Geocode(address string) (*geo.Location, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), geo.DefaultTimeout)
+	defer cancel()
+
+	return r.GeocodeContext(ctx, address)
+}
+
+// GeocodeContext returns the first acceptable location for address
+func (r *Racer) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	loc, _, err := r.GeocodeWithProvider(ctx, address)
+	return loc, err
+}
+
+// GeocodeWithProvider returns the first acceptable location for address along with the name of the geocoder that won.
+// When none does, the error joins the error of each geocoder asked, named after it.
+func (r *Racer) GeocodeWithProvider(ctx context.Context, address string) (*geo.Location, string, error) {
+	result, winner, err := race(ctx, r, func(ctx context.Context, g geo.Geocoder) (*geo.Result, error) {
+		result, err := geo.GeocodeBest(ctx, g, address)
+		if err == nil && r.accept != nil && !r.accept(*result) {
+			err = ErrNotAccepted
+		}
+		return result, err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return &result.Location, winner, nil
+}
+
+// ReverseGeocode returns address for location
+func (r *Racer) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), geo.DefaultTimeout)
+	defer cancel()
+
+	return r.ReverseGeocodeContext(ctx, lat, lng)
+}
+
+// ReverseGeocodeContext returns the first address found for location
+func (r *Racer) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	addr, _, err := r.ReverseGeocodeWithProvider(ctx, lat, lng)
+	return addr, err
+}
+
+// ReverseGeocodeWithProvider returns the first address found for location along with the name of the geocoder that won.
+// When none does, the error joins the error of each geocoder asked, named after it.
+func (r *Racer) ReverseGeocodeWithProvider(ctx context.Context, lat, lng float64) (*geo.Address, string, error) {
+	return race(ctx, r, func(ctx context.Context, g geo.Geocoder) (*geo.Address, error) {
+		addr, err := geo.AsContextGeocoder(g).ReverseGeocodeContext(ctx, lat, lng)
+		if err == nil && addr == nil {
+			err = geo.ErrNotFound
+		}
+		return addr, err
+	})
+}
+
+// race runs ask for the geocoders of r, as the hedge allows, until one of them succeeds
+func race[T any](ctx context.Context, r *Racer, ask func(context.Context, geo.Geocoder) (T, error)) (T, string, error) {
+	var zero T
+	if len(r.geocoders) == 0 {
+		return zero, "", geo.ErrNotFound
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type answer struct {
+		i   int
+		v   T
+		err error
+	}
+	answers := make(chan answer, len(r.geocoders))
+	started, running := 0, 0
+	start := func() {
+		i := started
+		started++
+		running++
+		go func() {
+			v, err := ask(ctx, r.geocoders[i])
+			answers <- answer{i, v, err}
+		}()
+	}
+
+	var hedge <-chan time.Time
+	var timer *time.Timer
+	if r.hedge > 0 {
+		start()
+		timer = time.NewTimer(r.hedge)
+		defer timer.Stop()
+		hedge = timer.C
+	} else {
+		for range r.geocoders {
+			start()
+		}
+	}
+
+	errs := make([]error, len(r.geocoders))
+	for running > 0 {
+		select {
+		case a := <-answers:
+			running--
+			if a.err == nil {
+				return a.v, r.names[a.i], nil
+			}
+			errs[a.i] = geo.MemberError(r.names[a.i], a.err)
+			if running > 0 || started == len(r.geocoders) {
+				continue
+			}
+			start()
+		case <-hedge:
+			start()
+		case <-ctx.Done():
+			return zero, "", geo.ContextError(ctx)
+		}
+		if started == len(r.geocoders) {
+			hedge = nil
+		} else if timer != nil {
+			timer.Reset(r.hedge)
+		}
+	}
+	if ctx.Err() != nil {
+		return zero, "", geo.ContextError(ctx)
+	}
+	return zero, "", errors.Join(errs...)
+}
@@ -0,0 +1,126 @@
+package race_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+	"github.com/codingsince1985/geo-golang/data"
+	"github.com/codingsince1985/geo-golang/race"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	addressFixture  = geo.Address{FormattedAddress: "64 Elizabeth Street, Melbourne, Victoria 3000, Australia"}
+	locationFixture = geo.Location{Lat: -37.814107, Lng: 144.96328}
+	fixtures        = data.Geocoder(
+		data.AddressToLocation{addressFixture: locationFixture},
+		data.LocationToAddress{locationFixture: addressFixture},
+	)
+	empty = data.Geocoder(data.AddressToLocation{}, data.LocationToAddress{})
+)
+
+// slowGeocoder answers like its Geocoder after delay, unless its context is done first
+type slowGeocoder struct {
+	geo.Geocoder
+	delay             time.Duration
+	called, cancelled atomic.Bool
+}
+
+func (g *slowGeocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	g.called.Store(true)
+	select {
+	case <-time.After(g.delay):
+		return g.Geocoder.Geocode(address)
+	case <-ctx.Done():
+		g.cancelled.Store(true)
+		return nil, ctx.Err()
+	}
+}
+
+func (g *slowGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	g.called.Store(true)
+	select {
+	case <-time.After(g.delay):
+		return g.Geocoder.ReverseGeocode(lat, lng)
+	case <-ctx.Done():
+		g.cancelled.Store(true)
+		return nil, ctx.Err()
+	}
+}
+
+func TestFastestWins(t *testing.T) {
+	slow := &slowGeocoder{Geocoder: fixtures, delay: time.Second}
+	fast := &slowGeocoder{Geocoder: fixtures, delay: 10 * time.Millisecond}
+	r := race.New([]geo.Geocoder{slow, fast}, race.WithNames("slow", "fast"))
+
+	start := time.Now()
+	loc, winner, err := r.GeocodeWithProvider(context.Background(), addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, locationFixture, *loc)
+	assert.Equal(t, "fast", winner)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	assert.Eventually(t, slow.cancelled.Load, time.Second, time.Millisecond)
+}
+
+func TestFailuresLose(t *testing.T) {
+	slow := &slowGeocoder{Geocoder: fixtures, delay: 20 * time.Millisecond}
+	addr, winner, err := race.New([]geo.Geocoder{empty, slow}).ReverseGeocodeWithProvider(context.Background(), locationFixture.Lat, locationFixture.Lng)
+	assert.NoError(t, err)
+	assert.Equal(t, addressFixture, *addr)
+	assert.Equal(t, "geocoder 2", winner)
+}
+
+func TestHedge(t *testing.T) {
+	first := &slowGeocoder{Geocoder: fixtures, delay: 10 * time.Millisecond}
+	second := &slowGeocoder{Geocoder: fixtures, delay: 10 * time.Millisecond}
+	_, winner, err := race.New([]geo.Geocoder{first, second}, race.WithHedge(200*time.Millisecond)).
+		GeocodeWithProvider(context.Background(), addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, "geocoder 1", winner)
+	assert.False(t, second.called.Load())
+
+	// the next geocoder is asked once the hedge delay is over
+	first = &slowGeocoder{Geocoder: fixtures, delay: time.Second}
+	_, winner, err = race.New([]geo.Geocoder{first, second}, race.WithHedge(20*time.Millisecond)).
+		GeocodeWithProvider(context.Background(), addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, "geocoder 2", winner)
+
+	// or as soon as every geocoder asked so far failed
+	start := time.Now()
+	_, winner, err = race.New([]geo.Geocoder{empty, fixtures}, race.WithHedge(time.Second)).
+		GeocodeWithProvider(context.Background(), addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, "geocoder 2", winner)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestAccept(t *testing.T) {
+	other := data.Geocoder(data.AddressToLocation{addressFixture: {Lat: 1, Lng: 1}}, data.LocationToAddress{})
+	r := race.New([]geo.Geocoder{other, &slowGeocoder{Geocoder: fixtures, delay: 20 * time.Millisecond}},
+		race.WithAccept(func(r geo.Result) bool { return r.Lat < 0 }))
+	loc, err := r.Geocode(addressFixture.FormattedAddress)
+	assert.NoError(t, err)
+	assert.Equal(t, locationFixture, *loc)
+}
+
+func TestEveryoneFails(t *testing.T) {
+	loc, err := race.Geocoder(empty, empty).Geocode("NOWHERE,TX")
+	assert.Nil(t, loc)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
+	assert.Contains(t, err.Error(), "geocoder 1: not found")
+	assert.Contains(t, err.Error(), "geocoder 2: not found")
+}
+
+func TestContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	slow := &slowGeocoder{Geocoder: fixtures, delay: time.Second}
+	_, err := race.New([]geo.Geocoder{slow}).GeocodeContext(ctx, addressFixture.FormattedAddress)
+	assert.ErrorIs(t, err, geo.ErrTimeout)
+}
@@ -0,0 +1,131 @@
+// Package ratelimit keeps geocoders within the request rate their provider allows
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+// Option configures a rate limited geocoder
+type Option func(*config)
+
+type config struct {
+	policy   *Policy
+	limiter  *Limiter
+	failFast bool
+}
+
+// WithPolicy replaces the default policy of the provider
+func WithPolicy(p Policy) Option { return func(c *config) { c.policy = &p } }
+
+// WithLimiter shares l with other geocoders, e.g. several instances of the same provider; it overrides WithPolicy
+func WithLimiter(l *Limiter) Option { return func(c *config) { c.limiter = l } }
+
+// WithFailFast fails requests that would have to wait for a token with an error wrapping geo.ErrQuotaExceeded,
+// whose RetryAfter is how long the wait would have been. By default requests wait for their token.
+func WithFailFast() Option { return func(c *config) { c.failFast = true } }
+
+// Geocoder limits the requests of geocoder, with the default policy of its provider unless WithPolicy says otherwise.
+// The provider is found through the geo.Wrappers around geocoder, e.g. a cached or retried geocoder. Give others,
+// which DefaultPolicy may not suit, their policy with WithPolicy.
+// Configurable geocoders keep every lookup they support, with each HTTP request they send taking a token,
+// batches included. Other geocoders are wrapped, with each geocode and reverse geocode taking a token.
+// Waiting for a token gives up when the context of the request is done.
+func Geocoder(geocoder geo.Geocoder, opts ...Option) geo.Geocoder {
+	provider := geo.ProviderName(geocoder)
+
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.limiter == nil {
+		p := PolicyFor(provider)
+		if c.policy != nil {
+			p = *c.policy
+		}
+		c.limiter = NewLimiter(p)
+	}
+
+	l := limiter{Limiter: c.limiter, provider: provider, failFast: c.failFast}
+	if _, ok := geocoder.(geo.Configurable); ok {
+		// first in the chain, so that other interceptors don't count the wait as part of the request
+		return geo.Configure(geocoder, func(o *geo.Options) {
+			o.Interceptors = append([]geo.Interceptor{l.interceptor()}, o.Interceptors...)
+		})
+	}
+	return limitedGeocoder{geocoder: geo.AsContextGeocoder(geocoder), limiter: l}
+}
+
+type limiter struct {
+	*Limiter
+	provider string
+	failFast bool
+}
+
+// acquire takes a token, or fails at once without one in fail fast mode
+func (l limiter) acquire(ctx context.Context) error {
+	if !l.failFast {
+		return l.Wait(ctx)
+	}
+	if ok, wait := l.Allow(); !ok {
+		// there is no response to speak of, only how long to wait before trying again
+		return &geo.ProviderError{
+			Provider: l.provider,
+			Status:   "rate limited",
+			Err:      geo.ErrQuotaExceeded,
+			Response: &geo.ResponseMeta{RetryAfter: wait.Round(time.Millisecond)},
+		}
+	}
+	return nil
+}
+
+func (l limiter) interceptor() geo.Interceptor {
+	return geo.Interceptor{
+		BeforeRequest: func(req *http.Request) (*http.Request, error) {
+			return req, l.acquire(req.Context())
+		},
+	}
+}
+
+type limitedGeocoder struct {
+	geocoder geo.ContextGeocoder
+	limiter  limiter
+}
+
+// Unwrap returns the geocoder whose lookups are limited
+func (g limitedGeocoder) Unwrap() geo.Geocoder { return g.geocoder }
+
+// Geocode returns location for address
+func (g limitedGeocoder) Geocode(address string) (*geo.Location, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), geo.DefaultTimeout)
+	defer cancel()
+
+	return g.GeocodeContext(ctx, address)
+}
+
+// GeocodeContext returns location for address once a token is taken
+func (g limitedGeocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	if err := g.limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	return g.geocoder.GeocodeContext(ctx, address)
+}
+
+// ReverseGeocode returns address for location
+func (g limitedGeocoder) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), geo.DefaultTimeout)
+	defer cancel()
+
+	return g.ReverseGeocodeContext(ctx, lat, lng)
+}
+
+// ReverseGeocodeContext returns address for location once a token is taken
+func (g limitedGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	if err := g.limiter.acquire(ctx); err != nil {
+		return nil, err
+	}
+	return g.geocoder.ReverseGeocodeContext(ctx, lat, lng)
+}
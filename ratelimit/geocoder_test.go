@@ -0,0 +1,148 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+	"github.com/codingsince1985/geo-golang/cached"
+	"github.com/codingsince1985/geo-golang/data"
+	"github.com/codingsince1985/geo-golang/openstreetmap"
+	"github.com/codingsince1985/geo-golang/ratelimit"
+	"github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	location = geo.Location{Lat: -37.814107, Lng: 144.96328}
+	fixtures = data.Geocoder(
+		data.AddressToLocation{geo.Address{FormattedAddress: "Melbourne"}: location},
+		data.LocationToAddress{},
+	)
+)
+
+func TestLimiterBurst(t *testing.T) {
+	l := ratelimit.NewLimiter(ratelimit.Policy{Rate: 10, Burst: 2})
+	for range 2 {
+		ok, _ := l.Allow()
+		assert.True(t, ok)
+	}
+	ok, wait := l.Allow()
+	assert.False(t, ok)
+	assert.InDelta(t, 100*time.Millisecond, wait, float64(10*time.Millisecond))
+
+	// a refused request does not keep its token
+	ok, again := l.Allow()
+	assert.False(t, ok)
+	assert.LessOrEqual(t, again, wait)
+}
+
+func TestLimiterWait(t *testing.T) {
+	l := ratelimit.NewLimiter(ratelimit.Policy{Rate: 20, Burst: 1})
+	start := time.Now()
+	for range 3 {
+		assert.NoError(t, l.Wait(context.Background()))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestLimiterWaitPastDeadline(t *testing.T) {
+	l := ratelimit.NewLimiter(ratelimit.Policy{Rate: 1, Burst: 1})
+	assert.NoError(t, l.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.ErrorIs(t, l.Wait(ctx), geo.ErrTimeout)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	l := ratelimit.NewLimiter(ratelimit.Policy{})
+	for range 100 {
+		ok, _ := l.Allow()
+		assert.True(t, ok)
+	}
+}
+
+func TestPolicyFor(t *testing.T) {
+	assert.Equal(t, ratelimit.Policy{Rate: 1, Burst: 1}, ratelimit.PolicyFor("openstreetmap"))
+	assert.Equal(t, ratelimit.Policy{Rate: 1, Burst: 1}, ratelimit.PolicyFor("pickpoint"))
+	assert.Equal(t, ratelimit.DefaultPolicy, ratelimit.PolicyFor("unknown"))
+}
+
+func TestGeocoderFailFast(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		resp.Write([]byte(`[{"lat":"-37.814107","lon":"144.96328","display_name":"Melbourne"}]`))
+	}))
+	defer ts.Close()
+
+	// Nominatim allows a single request per second
+	geocoder := ratelimit.Geocoder(openstreetmap.GeocoderWithURL(ts.URL+"/"), ratelimit.WithFailFast())
+	_, err := geocoder.Geocode("Melbourne")
+	assert.NoError(t, err)
+
+	_, err = geocoder.Geocode("Melbourne")
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+	var pe *geo.ProviderError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, "openstreetmap", pe.Provider)
+	assert.Greater(t, pe.RetryAfter(), 900*time.Millisecond)
+	assert.EqualValues(t, 1, requests.Load())
+
+	// every lookup of the provider is still there, and limited
+	_, err = geocoder.(geo.MultiGeocoder).GeocodeAll("Melbourne", 5)
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+}
+
+func TestGeocoderWrapped(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		resp.Write([]byte(`[{"lat":"-37.814107","lon":"144.96328","display_name":"Melbourne"}]`))
+	}))
+	defer ts.Close()
+
+	// the provider behind the cache still has its policy of a single request per second
+	geocoder := ratelimit.Geocoder(cached.Geocoder(openstreetmap.GeocoderWithURL(ts.URL+"/"), cache.New(cache.NoExpiration, 0)), ratelimit.WithFailFast())
+	_, err := geocoder.Geocode("Melbourne")
+	assert.NoError(t, err)
+
+	_, err = geocoder.Geocode("Melbourne VIC")
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+	var pe *geo.ProviderError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, "openstreetmap", pe.Provider)
+}
+
+func TestGeocoderSharedLimiter(t *testing.T) {
+	l := ratelimit.NewLimiter(ratelimit.Policy{Rate: 1, Burst: 1})
+	first := ratelimit.Geocoder(fixtures, ratelimit.WithLimiter(l), ratelimit.WithFailFast())
+	second := ratelimit.Geocoder(fixtures, ratelimit.WithLimiter(l), ratelimit.WithFailFast())
+
+	loc, err := first.Geocode("Melbourne")
+	assert.NoError(t, err)
+	assert.Equal(t, location, *loc)
+	_, err = second.ReverseGeocode(location.Lat, location.Lng)
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+}
+
+func TestGeocoderBlocking(t *testing.T) {
+	geocoder := ratelimit.Geocoder(fixtures, ratelimit.WithPolicy(ratelimit.Policy{Rate: 20, Burst: 1}))
+	start := time.Now()
+	for range 3 {
+		_, err := geocoder.Geocode("Melbourne")
+		assert.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := geocoder.(geo.ContextGeocoder).GeocodeContext(ctx, "Melbourne")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+// Policy is a token bucket: Rate requests per second on average, up to Burst of them at once.
+// A Rate that is not positive does not limit anything.
+type Policy struct {
+	Rate  float64
+	Burst int
+}
+
+// DefaultPolicy applies to providers without a policy in Defaults
+var DefaultPolicy = Policy{Rate: 10, Burst: 10}
+
+// Defaults are the policies of providers, by the name geo.HTTPGeocoder gives them, that publish a request rate.
+// They stay at or below the free tiers, raise them to what your plan allows.
+var Defaults = map[string]Policy{
+	// https://operations.osmfoundation.org/policies/nominatim/ allows an absolute maximum of 1 request per second
+	"openstreetmap": {Rate: 1, Burst: 1},
+	// Nominatim run by others, held to the policy of Nominatim unless your plan allows more
+	"mapquest/nominatim": {Rate: 1, Burst: 1},
+	"pickpoint":          {Rate: 1, Burst: 1},
+	"locationiq":         {Rate: 2, Burst: 2},
+	"google":             {Rate: 50, Burst: 50},
+	"yandex":             {Rate: 10, Burst: 10},
+	"opencage":           {Rate: 1, Burst: 1},
+	"mapbox":             {Rate: 10, Burst: 10},
+	"tomtom":             {Rate: 5, Burst: 5},
+}
+
+// PolicyFor returns the policy of provider, DefaultPolicy if it has none
+func PolicyFor(provider string) Policy {
+	if p, ok := Defaults[provider]; ok {
+		return p
+	}
+	return DefaultPolicy
+}
+
+// Limiter hands out tokens at the rate of a policy. It is safe for concurrent use,
+// so one limiter can be shared by every geocoder sending requests to the same provider.
+type Limiter struct {
+	policy Policy
+
+	mu sync.Mutex
+	// tokens is negative when requests are waiting for tokens to come
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a limiter for p, with a full bucket
+func NewLimiter(p Policy) *Limiter {
+	if p.Burst < 1 {
+		p.Burst = 1
+	}
+	return &Limiter{policy: p, tokens: float64(p.Burst)}
+}
+
+// Wait takes a token, waiting for one to come if there is none.
+// It returns ErrTimeout at once if the token would come after the deadline of ctx.
+func (l *Limiter) Wait(ctx context.Context) error {
+	wait := l.reserve(time.Now())
+	if wait == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		l.release()
+		return geo.ErrTimeout
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release()
+		return geo.ContextError(ctx)
+	}
+}
+
+// Allow takes a token if there is one, or returns how long until there is
+func (l *Limiter) Allow() (bool, time.Duration) {
+	if wait := l.reserve(time.Now()); wait > 0 {
+		l.release()
+		return false, wait
+	}
+	return true, 0
+}
+
+// reserve takes a token, which may not have come yet, and returns how long until it does
+func (l *Limiter) reserve(now time.Time) time.Duration {
+	if l.policy.Rate <= 0 {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens = min(float64(l.policy.Burst), l.tokens+now.Sub(l.last).Seconds()*l.policy.Rate)
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.policy.Rate * float64(time.Second))
+}
+
+// release gives back a token that was reserved but not used
+func (l *Limiter) release() {
+	if l.policy.Rate <= 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(float64(l.policy.Burst), l.tokens+1)
+}
@@ -0,0 +1,67 @@
+package geo
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResponseMeta describes the HTTP response of a provider beyond its body
+type ResponseMeta struct {
+	StatusCode int
+	// RetryAfter is how long the provider asked clients to wait, 0 if it did not say
+	RetryAfter time.Duration
+	RateLimit  RateLimit
+}
+
+// RateLimit is the request quota a provider reported in its response headers.
+// Limit is 0 when the provider did not report one.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// ParseResponseMeta reads the status, Retry-After and rate limit headers of resp.
+// Both the X-RateLimit-* and the RateLimit-* header families are understood.
+func ParseResponseMeta(resp *http.Response) ResponseMeta {
+	return parseResponseMeta(resp, time.Now())
+}
+
+func parseResponseMeta(resp *http.Response, now time.Time) ResponseMeta {
+	meta := ResponseMeta{StatusCode: resp.StatusCode}
+	h := resp.Header
+
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			meta.RetryAfter = time.Duration(secs) * time.Second
+		} else if t, err := http.ParseTime(v); err == nil && t.After(now) {
+			meta.RetryAfter = t.Sub(now)
+		}
+	}
+
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		limit, err := strconv.Atoi(h.Get(prefix + "Limit"))
+		if err != nil {
+			continue
+		}
+		meta.RateLimit.Limit = limit
+		meta.RateLimit.Remaining, _ = strconv.Atoi(h.Get(prefix + "Remaining"))
+		if reset, err := strconv.ParseInt(h.Get(prefix+"Reset"), 10, 64); err == nil {
+			meta.RateLimit.Reset = resetTime(reset, now)
+		}
+		break
+	}
+	return meta
+}
+
+// resetTime interprets a rate limit reset either as a Unix timestamp or as seconds from now,
+// as providers disagree on which one they send
+func resetTime(reset int64, now time.Time) time.Time {
+	const unixThreshold = 1_000_000_000
+	if reset >= unixThreshold {
+		return time.Unix(reset, 0)
+	}
+	return now.Add(time.Duration(reset) * time.Second)
+}
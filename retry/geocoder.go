@@ -0,0 +1,79 @@
+package retry
+
+import (
+	"context"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+type retryGeocoder struct {
+	geocoder geo.ContextGeocoder
+	multi    geo.MultiGeocoder
+	opts     []Option
+}
+
+// Geocoder retries the lookups of geocoder that fail with a transient error.
+// Geocoders without candidate lookups answer GeocodeAll with their single location.
+func Geocoder(geocoder geo.Geocoder, opts ...Option) geo.Geocoder {
+	g := retryGeocoder{geocoder: geo.AsContextGeocoder(geocoder), opts: opts}
+	g.multi, _ = geocoder.(geo.MultiGeocoder)
+	return g
+}
+
+// Unwrap returns the geocoder whose lookups are retried
+func (g retryGeocoder) Unwrap() geo.Geocoder { return g.geocoder }
+
+// Geocode returns location for address
+func (g retryGeocoder) Geocode(address string) (*geo.Location, error) {
+	return g.GeocodeContext(context.Background(), address)
+}
+
+// GeocodeContext returns location for address, retrying until ctx is done
+func (g retryGeocoder) GeocodeContext(ctx context.Context, address string) (*geo.Location, error) {
+	return Do(ctx, func(ctx context.Context) (*geo.Location, error) {
+		return g.geocoder.GeocodeContext(ctx, address)
+	}, g.opts...)
+}
+
+// GeocodeAll returns up to limit candidates for address
+func (g retryGeocoder) GeocodeAll(address string, limit int) ([]geo.Result, error) {
+	return g.GeocodeAllContext(context.Background(), address, limit)
+}
+
+// GeocodeAllContext returns up to limit candidates for address, retrying until ctx is done
+func (g retryGeocoder) GeocodeAllContext(ctx context.Context, address string, limit int) ([]geo.Result, error) {
+	return Do(ctx, func(ctx context.Context) ([]geo.Result, error) {
+		if g.multi != nil {
+			return g.multi.GeocodeAllContext(ctx, address, limit)
+		}
+		loc, err := g.geocoder.GeocodeContext(ctx, address)
+		if err != nil || loc == nil {
+			return nil, err
+		}
+		return []geo.Result{{Location: *loc}}, nil
+	}, g.opts...)
+}
+
+// GeocodeAddress returns location for a structured address
+func (g retryGeocoder) GeocodeAddress(address geo.Address) (*geo.Location, error) {
+	return g.GeocodeAddressContext(context.Background(), address)
+}
+
+// GeocodeAddressContext returns location for a structured address, retrying until ctx is done
+func (g retryGeocoder) GeocodeAddressContext(ctx context.Context, address geo.Address) (*geo.Location, error) {
+	return Do(ctx, func(ctx context.Context) (*geo.Location, error) {
+		return geo.AsStructuredGeocoder(g.geocoder).GeocodeAddressContext(ctx, address)
+	}, g.opts...)
+}
+
+// ReverseGeocode returns address for location
+func (g retryGeocoder) ReverseGeocode(lat, lng float64) (*geo.Address, error) {
+	return g.ReverseGeocodeContext(context.Background(), lat, lng)
+}
+
+// ReverseGeocodeContext returns address for location, retrying until ctx is done
+func (g retryGeocoder) ReverseGeocodeContext(ctx context.Context, lat, lng float64) (*geo.Address, error) {
+	return Do(ctx, func(ctx context.Context) (*geo.Address, error) {
+		return g.geocoder.ReverseGeocodeContext(ctx, lat, lng)
+	}, g.opts...)
+}
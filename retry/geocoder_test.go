@@ -0,0 +1,150 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+	"github.com/codingsince1985/geo-golang/openstreetmap"
+	"github.com/codingsince1985/geo-golang/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+const response = `[{"lat":"-37.814107","lon":"144.96328","display_name":"Melbourne"}]`
+
+// failingServer answers the first failures requests with status, setting the headers in header, then response
+func failingServer(failures int32, status int, header map[string]string) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if requests.Add(1) <= failures {
+			for k, v := range header {
+				resp.Header().Set(k, v)
+			}
+			resp.WriteHeader(status)
+			return
+		}
+		resp.Write([]byte(response))
+	}))
+	return ts, &requests
+}
+
+func TestGeocodeRetriesUnavailable(t *testing.T) {
+	ts, requests := failingServer(2, http.StatusServiceUnavailable, nil)
+	defer ts.Close()
+
+	var retries []int
+	geocoder := retry.Geocoder(openstreetmap.GeocoderWithURL(ts.URL+"/"),
+		retry.WithBackoff(time.Millisecond, 10*time.Millisecond),
+		retry.WithOnRetry(func(attempt int, err error, wait time.Duration) {
+			assert.ErrorIs(t, err, geo.ErrProviderUnavailable)
+			retries = append(retries, attempt)
+		}))
+	loc, err := geocoder.Geocode("Melbourne")
+	assert.NoError(t, err)
+	assert.Equal(t, geo.Location{Lat: -37.814107, Lng: 144.96328}, *loc)
+	assert.EqualValues(t, 3, requests.Load())
+	assert.Equal(t, []int{1, 2}, retries)
+}
+
+func TestGeocodeGivesUpAfterMaxAttempts(t *testing.T) {
+	ts, requests := failingServer(5, http.StatusBadGateway, nil)
+	defer ts.Close()
+
+	geocoder := retry.Geocoder(openstreetmap.GeocoderWithURL(ts.URL+"/"),
+		retry.WithMaxAttempts(2), retry.WithBackoff(time.Millisecond, 10*time.Millisecond))
+	_, err := geocoder.(geo.MultiGeocoder).GeocodeAll("Melbourne", 5)
+	assert.ErrorIs(t, err, geo.ErrProviderUnavailable)
+	assert.EqualValues(t, 2, requests.Load())
+}
+
+func TestGeocodeNeverRetriesRejectedRequests(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusBadRequest, http.StatusTooManyRequests} {
+		ts, requests := failingServer(1, status, nil)
+
+		geocoder := retry.Geocoder(openstreetmap.GeocoderWithURL(ts.URL+"/"), retry.WithBackoff(time.Millisecond, 10*time.Millisecond))
+		_, err := geocoder.Geocode("Melbourne")
+		assert.Error(t, err)
+		assert.EqualValues(t, 1, requests.Load(), "status %d", status)
+		ts.Close()
+	}
+}
+
+func TestGeocodeHonoursRetryAfter(t *testing.T) {
+	ts, requests := failingServer(1, http.StatusTooManyRequests, map[string]string{"Retry-After": "1"})
+	defer ts.Close()
+
+	// waiting longer than the most allowed fails at once
+	geocoder := retry.Geocoder(openstreetmap.GeocoderWithURL(ts.URL+"/"), retry.WithBackoff(time.Millisecond, 500*time.Millisecond))
+	_, err := geocoder.Geocode("Melbourne")
+	assert.ErrorIs(t, err, geo.ErrQuotaExceeded)
+	assert.EqualValues(t, 1, requests.Load())
+
+	requests.Store(0)
+	start := time.Now()
+	geocoder = retry.Geocoder(openstreetmap.GeocoderWithURL(ts.URL+"/"), retry.WithBackoff(time.Millisecond, 2*time.Second))
+	_, err = geocoder.Geocode("Melbourne")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, requests.Load())
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestGeocodeRetriesAttemptTimeout(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if requests.Add(1) == 1 {
+			<-req.Context().Done()
+			return
+		}
+		resp.Write([]byte(response))
+	}))
+	defer ts.Close()
+
+	geocoder := retry.Geocoder(openstreetmap.GeocoderWithURL(ts.URL+"/"),
+		retry.WithAttemptTimeout(50*time.Millisecond), retry.WithBackoff(time.Millisecond, 10*time.Millisecond))
+	_, err := geocoder.Geocode("Melbourne")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, requests.Load())
+}
+
+func TestDoStopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	attempts := 0
+	_, err := retry.Do(ctx, func(ctx context.Context) (int, error) {
+		attempts++
+		return 0, geo.ErrProviderUnavailable
+	}, retry.WithMaxAttempts(100), retry.WithBackoff(time.Second, time.Second))
+	assert.ErrorIs(t, err, geo.ErrProviderUnavailable)
+	assert.Less(t, attempts, 100)
+}
+
+func TestDoIgnoresNegativeBackoff(t *testing.T) {
+	attempts := 0
+	assert.NotPanics(t, func() {
+		_, err := retry.Do(context.Background(), func(ctx context.Context) (int, error) {
+			if attempts++; attempts == 1 {
+				return 0, geo.ErrProviderUnavailable
+			}
+			return 1, nil
+		}, retry.WithBackoff(-time.Second, -time.Second))
+		assert.NoError(t, err)
+	})
+	assert.Equal(t, 2, attempts)
+}
+
+func TestTransient(t *testing.T) {
+	assert.True(t, retry.Transient(geo.ErrTimeout))
+	assert.True(t, retry.Transient(geo.NamedError("google", geo.ErrProviderUnavailable)))
+	assert.True(t, retry.Transient(&geo.ProviderError{Err: geo.ErrQuotaExceeded, Response: &geo.ResponseMeta{RetryAfter: time.Second}}))
+	assert.False(t, retry.Transient(geo.ErrQuotaExceeded))
+	assert.False(t, retry.Transient(geo.NamedError("google", geo.ErrUnauthorized)))
+	assert.False(t, retry.Transient(geo.ErrInvalidRequest))
+	assert.False(t, retry.Transient(geo.ErrNotFound))
+	assert.False(t, retry.Transient(errors.New("boom")))
+}
@@ -0,0 +1,137 @@
+// Package retry retries geocoding requests that failed for reasons likely to go away
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/codingsince1985/geo-golang"
+)
+
+const (
+	// DefaultMaxAttempts is how many times a request is sent unless WithMaxAttempts says otherwise
+	DefaultMaxAttempts = 3
+	// DefaultInitialDelay and DefaultMaxDelay bound the wait between two attempts, which doubles from one to the next
+	DefaultInitialDelay = 250 * time.Millisecond
+	DefaultMaxDelay     = 10 * time.Second
+)
+
+// Option configures how requests are retried
+type Option func(*policy)
+
+type policy struct {
+	maxAttempts            int
+	initialDelay, maxDelay time.Duration
+	attemptTimeout         time.Duration
+	retryable              func(error) bool
+	onRetry                func(attempt int, err error, wait time.Duration)
+}
+
+// WithMaxAttempts sets how many times a request is sent at most, the first time included
+func WithMaxAttempts(n int) Option { return func(p *policy) { p.maxAttempts = max(1, n) } }
+
+// WithBackoff sets the wait before the second attempt, and the most any wait may be.
+// Retry-After asked by the provider is honoured up to max, the request fails instead of waiting any longer.
+// Negative durations keep the defaults.
+func WithBackoff(initial, max time.Duration) Option {
+	return func(p *policy) {
+		if initial >= 0 {
+			p.initialDelay = initial
+		}
+		if max >= 0 {
+			p.maxDelay = max
+		}
+	}
+}
+
+// WithAttemptTimeout gives each attempt a deadline of its own, geo.DefaultTimeout by default, 0 for none.
+// An attempt running out of time fails with geo.ErrTimeout, which is retried as long as ctx is not done.
+func WithAttemptTimeout(d time.Duration) Option { return func(p *policy) { p.attemptTimeout = d } }
+
+// WithClassifier replaces Transient to decide which errors are retried
+func WithClassifier(retryable func(error) bool) Option {
+	return func(p *policy) { p.retryable = retryable }
+}
+
+// WithOnRetry has onRetry called before waiting to retry a failed attempt, e.g. to log it
+func WithOnRetry(onRetry func(attempt int, err error, wait time.Duration)) Option {
+	return func(p *policy) { p.onRetry = onRetry }
+}
+
+func newPolicy(opts []Option) policy {
+	p := policy{
+		maxAttempts:    DefaultMaxAttempts,
+		initialDelay:   DefaultInitialDelay,
+		maxDelay:       DefaultMaxDelay,
+		attemptTimeout: geo.DefaultTimeout,
+		retryable:      Transient,
+	}
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
+
+// Transient reports whether err is likely to go away: timeouts, unavailable providers,
+// and rate limits the provider said when to retry. Rejected credentials or queries never are.
+// An exhausted quota without Retry-After is not retried, as it usually lasts until the next day or month.
+func Transient(err error) bool {
+	switch {
+	case errors.Is(err, geo.ErrUnauthorized), errors.Is(err, geo.ErrInvalidRequest),
+		errors.Is(err, geo.ErrNotFound), errors.Is(err, geo.ErrUnsupported):
+		return false
+	case errors.Is(err, geo.ErrTimeout), errors.Is(err, geo.ErrProviderUnavailable):
+		return true
+	case errors.Is(err, geo.ErrQuotaExceeded):
+		return retryAfter(err) > 0
+	}
+	return false
+}
+
+// retryAfter returns how long the provider asked to wait, 0 if it did not say
+func retryAfter(err error) time.Duration {
+	var ra interface{ RetryAfter() time.Duration }
+	if errors.As(err, &ra) {
+		return ra.RetryAfter()
+	}
+	return 0
+}
+
+// Do calls f until it succeeds, fails with an error that is not retryable, or has been called as often as allowed.
+// Attempts are spaced by exponential backoff with full jitter, or by the Retry-After of the last error.
+// It gives up when ctx is done, or when the wait would outlast its deadline, returning the last error of f.
+func Do[T any](ctx context.Context, f func(ctx context.Context) (T, error), opts ...Option) (T, error) {
+	p := newPolicy(opts)
+	delay := p.initialDelay
+	for n := 1; ; n++ {
+		v, err := geo.WithinTimeout(ctx, p.attemptTimeout, f)
+		if err == nil || n >= p.maxAttempts || ctx.Err() != nil || !p.retryable(err) {
+			if err != nil && ctx.Err() != nil {
+				err = geo.ContextError(ctx)
+			}
+			return v, err
+		}
+
+		wait := retryAfter(err)
+		if wait == 0 {
+			wait = rand.N(delay + 1)
+			delay = min(2*delay, p.maxDelay)
+		}
+		if deadline, ok := ctx.Deadline(); wait > p.maxDelay || ok && time.Until(deadline) < wait {
+			return v, err
+		}
+		if p.onRetry != nil {
+			p.onRetry(n, err, wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return v, err
+		}
+	}
+}
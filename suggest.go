@@ -0,0 +1,112 @@
+package geo
+
+import (
+	"context"
+	"net/url"
+)
+
+// DefaultSuggestLimit is the number of suggestions Suggest asks for when given a non-positive limit
+const DefaultSuggestLimit = 5
+
+// Suggestion is a completion of a partial address, offered while the user types
+type Suggestion struct {
+	// ID identifies the suggestion to the provider when it is resolved
+	ID string
+	// Text is what to show the user
+	Text string
+	// Result is set when the provider answers suggestions with their location,
+	// in which case resolving the suggestion sends no further request
+	Result *Result
+}
+
+// SuggestOptions narrows down the suggestions for a partial address.
+// The Filter a geocoder is configured with applies too; Proximity given here takes precedence over its own.
+type SuggestOptions struct {
+	// Limit caps the number of suggestions, DefaultSuggestLimit if not positive
+	Limit int
+	// Proximity biases suggestions toward this location, typically where the user is
+	Proximity *Location
+}
+
+// Suggester completes partial addresses as the user types and resolves the suggestion picked into a Result
+type Suggester interface {
+	Suggest(ctx context.Context, partial string, opts SuggestOptions) ([]Suggestion, error)
+	Resolve(ctx context.Context, s Suggestion) (*Result, error)
+}
+
+// SuggestEndpointBuilder is implemented by EndpointBuilders whose provider has an autocomplete endpoint.
+// SuggestURL maps opts.Limit and opts.Proximity itself; the Filter of the geocoder is applied afterwards.
+type SuggestEndpointBuilder interface {
+	SuggestURL(partial string, opts SuggestOptions) string
+}
+
+// ResolveEndpointBuilder is implemented by EndpointBuilders whose provider answers suggestions
+// without their location, so that looking one up takes a request of its own
+type ResolveEndpointBuilder interface {
+	ResolveURL(s Suggestion) string
+}
+
+// SuggestResponseParser is implemented by ResponseParsers that can parse the response of an autocomplete endpoint
+type SuggestResponseParser interface {
+	Suggestions() ([]Suggestion, error)
+}
+
+// Suggest returns up to opts.Limit completions of partial, best first.
+// No suggestions is not an error, as partial addresses often have none yet.
+func (g HTTPGeocoder) Suggest(ctx context.Context, partial string, opts SuggestOptions) ([]Suggestion, error) {
+	b, ok := g.EndpointBuilder.(SuggestEndpointBuilder)
+	if !ok {
+		return nil, NamedError(g.Provider, UnsupportedError("suggestions"))
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultSuggestLimit
+	}
+
+	f := g.Options.Filter
+	if opts.Proximity != nil {
+		f.Proximity = nil
+	}
+	u, err := g.filterWith(b.SuggestURL(url.QueryEscape(partial), opts), f)
+	if err != nil {
+		return nil, err
+	}
+	responseParser := g.ResponseParserFactory()
+	if err := g.response(ctx, u, responseParser); err != nil {
+		return nil, err
+	}
+
+	p, ok := responseParser.(SuggestResponseParser)
+	if !ok {
+		return nil, NamedError(g.Provider, UnsupportedError("suggestions"))
+	}
+	suggestions, err := p.Suggestions()
+	if err != nil {
+		return nil, NamedError(g.Provider, err)
+	}
+	if len(suggestions) > opts.Limit {
+		suggestions = suggestions[:opts.Limit]
+	}
+	return suggestions, nil
+}
+
+// Resolve returns the result s stands for, looking it up by its ID if it came without one
+func (g HTTPGeocoder) Resolve(ctx context.Context, s Suggestion) (*Result, error) {
+	if s.Result != nil {
+		r := *s.Result
+		return &r, nil
+	}
+	b, ok := g.EndpointBuilder.(ResolveEndpointBuilder)
+	if !ok || s.ID == "" {
+		return nil, NamedError(g.Provider, UnsupportedError("resolving suggestions without a result"))
+	}
+
+	responseParser := g.ResponseParserFactory()
+	if err := g.response(ctx, b.ResolveURL(s), responseParser); err != nil {
+		return nil, err
+	}
+	results, err := candidates(responseParser)
+	if err != nil {
+		return nil, NamedError(g.Provider, err)
+	}
+	return &results[0], nil
+}
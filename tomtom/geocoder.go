@@ -3,6 +3,7 @@ package tomtom
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 
 	geo "github.com/codingsince1985/geo-golang"
@@ -16,10 +17,30 @@ type (
 		}
 
 		Results []struct {
+			ID              string
+			Type            string
+			EntityType      string
+			MatchConfidence struct {
+				Score float64
+			}
 			Position struct {
 				Lat float64
 				Lon float64
 			}
+			Address struct {
+				FreeformAddress string
+			}
+			// Poi and Dist are set for points of interest
+			Poi struct {
+				Name       string
+				Categories []string
+			}
+			Dist     float64
+			Viewport struct {
+				TopLeftPoint, BtmRightPoint struct {
+					Lat, Lon float64
+				}
+			}
 		}
 
 		// Reverse Geocoding response
@@ -50,6 +71,7 @@ func Geocoder(key string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getUrl(key, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "tomtom",
 	}
 }
 
@@ -65,6 +87,82 @@ func (b baseURL) GeocodeURL(address string) string {
 	return strings.Replace(string(b), "*", params, 1)
 }
 
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return b.GeocodeURL(address) + fmt.Sprintf("&limit=%d", limit)
+}
+
+// StructuredGeocodeURL uses the structured geocode endpoint, which requires a country code.
+// Addresses without one are looked up by their single line form instead.
+func (b baseURL) StructuredGeocodeURL(a geo.Address) string {
+	if a.CountryCode == "" {
+		return b.GeocodeURL(url.QueryEscape(a.SingleLine()))
+	}
+
+	v := url.Values{"countryCode": {a.CountryCode}}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	set("streetNumber", a.HouseNumber)
+	set("streetName", a.Street)
+	set("municipalitySubdivision", a.Suburb)
+	set("municipality", a.City)
+	set("countrySecondarySubdivision", a.County)
+	set("countrySubdivision", a.State)
+	set("postalCode", a.Postcode)
+	return strings.Replace(string(b), "*", "structuredGeocode.json", 1) + "&" + v.Encode()
+}
+
+// SuggestURL uses the search endpoint in typeahead mode, which takes the query as incomplete
+func (b baseURL) SuggestURL(partial string, opts geo.SuggestOptions) string {
+	params := fmt.Sprintf("search/%s.json", partial)
+	u := strings.Replace(string(b), "*", params, 1) + fmt.Sprintf("&typeahead=true&limit=%d", opts.Limit)
+	if p := opts.Proximity; p != nil {
+		u += fmt.Sprintf("&lat=%f&lon=%f", p.Lat, p.Lng)
+	}
+	return u
+}
+
+// PlaceSearchURL uses POI search, or nearby search for every place around q.Near if there is no text
+func (b baseURL) PlaceSearchURL(text string, q geo.PlaceQuery) string {
+	params := fmt.Sprintf("poiSearch/%s.json", text)
+	if text == "" {
+		params = "nearbySearch/.json"
+	}
+	u := strings.Replace(string(b), "*", params, 1) + fmt.Sprintf("&limit=%d", q.Limit)
+	if n := q.Near; n != nil {
+		u += fmt.Sprintf("&lat=%f&lon=%f&radius=%.0f", n.Lat, n.Lng, q.Radius)
+	}
+	return u
+}
+
+func (b baseURL) LanguageParams(language string) url.Values {
+	return url.Values{"language": {language}}
+}
+
+// FilterURL maps f onto countrySet, topLeft and btmRight, and lat and lon.
+// TomTom can only restrict to bounds, not bias toward them.
+func (b baseURL) FilterURL(rawURL string, f geo.Filter) (string, error) {
+	if f.Bounds != nil && !f.RestrictToBounds {
+		return "", geo.UnsupportedError("bounds bias")
+	}
+
+	params := url.Values{}
+	if len(f.Countries) > 0 {
+		params.Set("countrySet", strings.ToUpper(strings.Join(f.Countries, ",")))
+	}
+	if bb := f.Bounds; bb != nil {
+		params.Set("topLeft", fmt.Sprintf("%f,%f", bb.North, bb.West))
+		params.Set("btmRight", fmt.Sprintf("%f,%f", bb.South, bb.East))
+	}
+	if p := f.Proximity; p != nil {
+		params.Set("lat", fmt.Sprintf("%f", p.Lat))
+		params.Set("lon", fmt.Sprintf("%f", p.Lng))
+	}
+	return geo.SetParams(rawURL, params), nil
+}
+
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
 	params := fmt.Sprintf("reverseGeocode/%f,%f", l.Lat, l.Lng)
 	return strings.Replace(string(b), "*", params, 1)
@@ -82,6 +180,78 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 	return nil, nil
 }
 
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	results := make([]geo.Result, 0, len(r.Results))
+	for _, res := range r.Results {
+		result := geo.Result{
+			Location:         geo.Location{Lat: res.Position.Lat, Lng: res.Position.Lon},
+			FormattedAddress: res.Address.FreeformAddress,
+			PlaceType:        res.Type,
+			Precision:        tomtomPrecision(res.Type, res.EntityType),
+			Confidence:       res.MatchConfidence.Score,
+		}
+		if v := res.Viewport; v.TopLeftPoint.Lat != 0 || v.BtmRightPoint.Lat != 0 {
+			result.BoundingBox = &geo.BoundingBox{
+				South: v.BtmRightPoint.Lat,
+				West:  v.TopLeftPoint.Lon,
+				North: v.TopLeftPoint.Lat,
+				East:  v.BtmRightPoint.Lon,
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (r *geocodeResponse) Suggestions() ([]geo.Suggestion, error) {
+	results, err := r.Candidates()
+	if err != nil {
+		return nil, err
+	}
+	suggestions := make([]geo.Suggestion, len(results))
+	for i := range results {
+		suggestions[i] = geo.Suggestion{ID: r.Results[i].ID, Text: results[i].FormattedAddress, Result: &results[i]}
+	}
+	return suggestions, nil
+}
+
+func (r *geocodeResponse) PlaceResults() ([]geo.Place, error) {
+	places := make([]geo.Place, 0, len(r.Results))
+	for _, res := range r.Results {
+		place := geo.Place{
+			ID:       res.ID,
+			Name:     res.Poi.Name,
+			Location: geo.Location{Lat: res.Position.Lat, Lng: res.Position.Lon},
+			Address:  res.Address.FreeformAddress,
+			Distance: res.Dist,
+		}
+		if len(res.Poi.Categories) > 0 {
+			place.Category = res.Poi.Categories[0]
+		}
+		places = append(places, place)
+	}
+	return places, nil
+}
+
+func tomtomPrecision(typ, entityType string) geo.Precision {
+	switch typ {
+	case "Point Address", "POI":
+		return geo.PrecisionRooftop
+	case "Address Range", "Street", "Cross Street":
+		return geo.PrecisionStreet
+	case "Geography":
+		switch entityType {
+		case "Country":
+			return geo.PrecisionCountry
+		case "CountrySubdivision", "CountrySecondarySubdivision", "CountryTertiarySubdivision":
+			return geo.PrecisionRegion
+		default:
+			return geo.PrecisionCity
+		}
+	}
+	return geo.PrecisionUnknown
+}
+
 func (r *geocodeResponse) Address() (*geo.Address, error) {
 	if len(r.Addresses) > 0 {
 		a := r.Addresses[0].Address
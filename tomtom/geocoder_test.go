@@ -1,6 +1,7 @@
 package tomtom
 
 import (
+	"context"
 	"math"
 	"net/http"
 	"net/http/httptest"
@@ -61,6 +62,65 @@ func TestReverseGeocode(t *testing.T) {
 	}
 }
 
+func TestSuggest(t *testing.T) {
+	var req *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, r *http.Request) {
+		req = r
+		resp.Write([]byte(geocodeResp))
+	}))
+	defer ts.Close()
+
+	geocoder := Geocoder(key, ts.URL+"/*?key="+key).(geo.Suggester)
+	suggestions, err := geocoder.Suggest(context.Background(), "1109 N Highl", geo.SuggestOptions{Proximity: &geo.Location{Lat: 38.9, Lng: -77.1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.Path != "/search/1109+N+Highl.json" || req.URL.Query().Get("typeahead") != "true" || req.URL.Query().Get("lat") != "38.900000" {
+		t.Fatalf("Got: %s\n", req.URL)
+	}
+	if len(suggestions) != 1 || suggestions[0].ID != "US/PAD/p0/26924656" || suggestions[0].Result == nil {
+		t.Fatalf("Got: %v\n", suggestions)
+	}
+
+	ts.Close()
+	result, err := geocoder.Resolve(context.Background(), suggestions[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Lat-38.88669) > eps || math.Abs(result.Lng+77.09464) > eps {
+		t.Fatalf("Got: %v\n", result)
+	}
+}
+
+func TestSearchPlaces(t *testing.T) {
+	var req *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, r *http.Request) {
+		req = r
+		resp.Write([]byte(poiResp))
+	}))
+	defer ts.Close()
+
+	geocoder := Geocoder(key, ts.URL+"/*?key="+key).(geo.PlaceSearcher)
+	places, err := geocoder.SearchPlaces(context.Background(), geo.PlaceQuery{Near: &geo.Location{Lat: 38.88669, Lng: -77.09464}, Radius: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.Path != "/nearbySearch/.json" || req.URL.Query().Get("radius") != "500" {
+		t.Fatalf("Got: %s\n", req.URL)
+	}
+	expected := geo.Place{
+		ID:       "g6JpZK84NDAwMDkwMDAzNzc0NzOhY6NVU0GhdqdVbmlmaWVk",
+		Name:     "Northside Social",
+		Category: "café",
+		Location: geo.Location{Lat: 38.88751, Lng: -77.09423},
+		Address:  "3211 Wilson Blvd, Arlington, VA 22201",
+		Distance: 97.4,
+	}
+	if len(places) != 1 || places[0] != expected {
+		t.Fatalf("Got: %v\tExpected: %v\n", places, expected)
+	}
+}
+
 func testServer(response string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		resp.Write([]byte(response))
@@ -70,6 +130,32 @@ func testServer(response string) *httptest.Server {
 const (
 	eps = 1.0e-5
 
+	poiResp = `
+{
+    "summary": {
+        "queryType": "NEARBY",
+        "numResults": 1
+    },
+    "results": [
+        {
+            "type": "POI",
+            "id": "g6JpZK84NDAwMDkwMDAzNzc0NzOhY6NVU0GhdqdVbmlmaWVk",
+            "dist": 97.4,
+            "poi": {
+                "name": "Northside Social",
+                "categories": ["café", "café/pub"]
+            },
+            "address": {
+                "freeformAddress": "3211 Wilson Blvd, Arlington, VA 22201"
+            },
+            "position": {
+                "lat": 38.88751,
+                "lon": -77.09423
+            }
+        }
+    ]
+}`
+
 	geocodeResp = `
 {
     "summary": {
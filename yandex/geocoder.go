@@ -3,6 +3,7 @@ package yandex
 
 import (
 	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -73,6 +74,7 @@ func Geocoder(apiKey string, baseURLs ...string) geo.Geocoder {
 	return geo.HTTPGeocoder{
 		EndpointBuilder:       baseURL(getURL(apiKey, baseURLs...)),
 		ResponseParserFactory: func() geo.ResponseParser { return &geocodeResponse{} },
+		Provider:              "yandex",
 	}
 }
 
@@ -80,15 +82,39 @@ func getURL(apiKey string, baseURLs ...string) string {
 	if len(baseURLs) > 0 {
 		return baseURLs[0]
 	}
-	return fmt.Sprintf("https://geocode-maps.yandex.ru/1.x/?results=1&lang=en_US&format=json&apikey=%s&", apiKey)
+	return fmt.Sprintf("https://geocode-maps.yandex.ru/1.x/?lang=en_US&format=json&apikey=%s&", apiKey)
 }
 
-func (b baseURL) GeocodeURL(address string) string {
-	return string(b) + "geocode=" + address
+func (b baseURL) GeocodeURL(address string) string { return b.GeocodeAllURL(address, 1) }
+
+func (b baseURL) GeocodeAllURL(address string, limit int) string {
+	return string(b) + fmt.Sprintf("results=%d&geocode=", limit) + address
+}
+
+// yandexLocales are the default locales of the languages Yandex answers in
+var yandexLocales = map[string]string{"ru": "ru_RU", "uk": "uk_UA", "be": "be_BY", "en": "en_US", "tr": "tr_TR"}
+
+// yandexRegionalLocales are the locales Yandex accepts besides the default ones
+var yandexRegionalLocales = map[string]bool{"en_RU": true}
+
+// LanguageParams maps language onto a Yandex locale, e.g. "uk" onto uk_UA and "en-RU" onto en_RU.
+// Regions Yandex has no locale for get the default locale of the language, e.g. "en-GB" gets en_US,
+// and languages Yandex does not support keep the default en_US.
+func (b baseURL) LanguageParams(language string) url.Values {
+	lang, region, _ := strings.Cut(strings.ReplaceAll(language, "-", "_"), "_")
+	lang = strings.ToLower(lang)
+	locale, ok := yandexLocales[lang]
+	if !ok {
+		return nil
+	}
+	if regional := lang + "_" + strings.ToUpper(region); yandexRegionalLocales[regional] {
+		locale = regional
+	}
+	return url.Values{"lang": {locale}}
 }
 
 func (b baseURL) ReverseGeocodeURL(l geo.Location) string {
-	return string(b) + fmt.Sprintf("sco=latlong&geocode=%f,%f", l.Lat, l.Lng)
+	return string(b) + fmt.Sprintf("results=1&sco=latlong&geocode=%f,%f", l.Lat, l.Lng)
 }
 
 func (r *geocodeResponse) Location() (*geo.Location, error) {
@@ -99,15 +125,65 @@ func (r *geocodeResponse) Location() (*geo.Location, error) {
 		return nil, nil
 	}
 	featureMember := r.Response.GeoObjectCollection.FeatureMember[0]
-	result := &geo.Location{}
-	latLng := strings.Split(featureMember.GeoObject.Point.Pos, " ")
+	result := parseYandexPoint(featureMember)
+
+	return &result, nil
+}
+
+func (r *geocodeResponse) Candidates() ([]geo.Result, error) {
+	members := r.Response.GeoObjectCollection.FeatureMember
+	results := make([]geo.Result, 0, len(members))
+	for _, m := range members {
+		meta := m.GeoObject.MetaDataProperty.GeocoderMetaData
+		result := geo.Result{
+			Location:         parseYandexPoint(m),
+			FormattedAddress: meta.Text,
+			PlaceType:        meta.Kind,
+			Precision:        yandexPrecision(meta.Precision, meta.Kind),
+		}
+		env := m.GeoObject.BoundedBy.Envelope
+		lower, upper := strings.Fields(env.LowerCorner), strings.Fields(env.UpperCorner)
+		if len(lower) == 2 && len(upper) == 2 {
+			// corners are given as "long lat" too
+			result.BoundingBox = &geo.BoundingBox{
+				South: geo.ParseFloat(lower[1]),
+				West:  geo.ParseFloat(lower[0]),
+				North: geo.ParseFloat(upper[1]),
+				East:  geo.ParseFloat(upper[0]),
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func yandexPrecision(precision, kind string) geo.Precision {
+	switch precision {
+	case "exact", "number", "near":
+		return geo.PrecisionRooftop
+	case "range", "street":
+		return geo.PrecisionStreet
+	}
+	switch kind {
+	case componentTypeLocality, "district":
+		return geo.PrecisionCity
+	case componentTypeState, componentTypeStateDistrict:
+		return geo.PrecisionRegion
+	case componentTypeCountry:
+		return geo.PrecisionCountry
+	}
+	return geo.PrecisionUnknown
+}
+
+func parseYandexPoint(r *yandexFeatureMember) geo.Location {
+	result := geo.Location{}
+	latLng := strings.Split(r.GeoObject.Point.Pos, " ")
 	if len(latLng) > 1 {
 		// Yandex return geo coord in format "long lat"
 		result.Lat, _ = strconv.ParseFloat(latLng[1], 64)
 		result.Lng, _ = strconv.ParseFloat(latLng[0], 64)
 	}
-
-	return result, nil
+	return result
 }
 
 func (r *geocodeResponse) Address() (*geo.Address, error) {
@@ -35,13 +35,29 @@ func TestReverseGeocode(t *testing.T) {
 	assert.True(t, strings.HasPrefix(address.Street, "Collins Street"))
 }
 
+func TestReverseGeocodeWithLanguage(t *testing.T) {
+	var lang []string
+	ts := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		lang = req.URL.Query()["lang"]
+		resp.Write([]byte(response2))
+	}))
+	defer ts.Close()
+
+	geocoder := yandex.Geocoder(token, ts.URL+"/?lang=en_US&format=json&")
+	for language, locale := range map[string]string{"": "en_US", "uk": "uk_UA", "en-RU": "en_RU", "en-GB": "en_US", "ru-UA": "ru_RU", "fr": "en_US"} {
+		_, err := geo.Configure(geocoder, geo.WithLanguage(language)).ReverseGeocode(37.816939, 144.961515)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{locale}, lang)
+	}
+}
+
 func TestReverseGeocodeWithNoResult(t *testing.T) {
 	ts := testServer(response3)
 	defer ts.Close()
 
 	geocoder := yandex.Geocoder(token, ts.URL+"/")
 	addr, err := geocoder.ReverseGeocode(-37.8137683, 164.9718448)
-	assert.Nil(t, err)
+	assert.ErrorIs(t, err, geo.ErrNotFound)
 	assert.Nil(t, addr)
 }
 